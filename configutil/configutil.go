@@ -24,6 +24,18 @@ const (
 //Config represents the user settings of which canteen he usually visits for eating
 type Config struct {
 	Canteen requests.Canteen `json:"canteen"`
+	//APIURL is the URL of the OpenMensa API, when empty the official openmensa.org endpoint is used
+	APIURL string `json:"apiURL,omitempty"`
+	//Retries is how often a failed request is repeated, when nil the default of the requests package is used
+	Retries *int `json:"retries,omitempty"`
+	//RetryBaseDelay is the delay before the first retry as Go duration, f.e. 500ms, it doubles with every further retry, when empty the default of the requests package is used
+	RetryBaseDelay string `json:"retryBaseDelay,omitempty"`
+	//RetryMaxDelay is the longest delay between two attempts as Go duration, f.e. 10s, when empty the default of the requests package is used
+	RetryMaxDelay string `json:"retryMaxDelay,omitempty"`
+	//RateLimit is the maximal number of requests per second to the OpenMensa API, 0 means no limit
+	RateLimit float64 `json:"rateLimit,omitempty"`
+	//Color decides when the output is colored: always, never or auto, which is used when it is empty
+	Color string `json:"color,omitempty"`
 }
 
 //SaveConfig saves a user configuration to the .config/gomensa folder, the config file is saved as a json file
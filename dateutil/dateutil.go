@@ -0,0 +1,166 @@
+package dateutil
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//Layout is the date format of the OpenMensa API, f.e. 2020-01-31
+	Layout = "2006-01-02"
+	//germanLayout is the usual german date format, f.e. 31.01.2020
+	germanLayout = "2.1.2006"
+)
+
+//ErrInvalidDate is returned when a date can not be parsed or does not exist in the calendar
+var ErrInvalidDate = errors.New("unknown date, expected f.e. YYYY-MM-DD, DD.MM.YYYY, today, tomorrow, friday, next friday, +3 or in 2 days")
+
+var (
+	//relativeDaysRegex matches a number of days relative to today, f.e. +3, -1, in 2 days or in 3 tagen
+	relativeDaysRegex = regexp.MustCompile(`^(?:([+-]\d+)|in (\d+) (?:days?|tagen?))$`)
+	//nextWeekdayRegex matches a weekday after today, f.e. next friday or nächsten freitag
+	nextWeekdayRegex = regexp.MustCompile(`^(?:next|nächste[nrs]?|naechste[nrs]?|kommende[nrs]?) (\p{L}+)$`)
+	//germanDateWithoutYearRegex matches a german date without a year, f.e. 31.01. or 31.1
+	germanDateWithoutYearRegex = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})\.?$`)
+)
+
+//relativeDays are the words for days relative to today
+var relativeDays = map[string]int{
+	"today":                0,
+	"heute":                0,
+	"tomorrow":             1,
+	"morgen":               1,
+	"day after tomorrow":   2,
+	"übermorgen":           2,
+	"uebermorgen":          2,
+	"yesterday":            -1,
+	"gestern":              -1,
+	"day before yesterday": -2,
+	"vorgestern":           -2,
+}
+
+//weekdays are the english and german names of the weekdays and their abbreviations
+var weekdays = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday, "montag": time.Monday, "mo": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "dienstag": time.Tuesday, "di": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday, "mittwoch": time.Wednesday, "mi": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "donnerstag": time.Thursday, "do": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "freitag": time.Friday, "fr": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday, "samstag": time.Saturday, "sonnabend": time.Saturday, "sa": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday, "sonntag": time.Sunday, "so": time.Sunday,
+}
+
+//Parse returns the date described by value relative to now, the time of the returned date is midnight in the location of now
+//it understands YYYY-MM-DD, DD.MM.YYYY, DD.MM., today, tomorrow, weekdays like friday, next friday, +3, -1, in 2 days and their german equivalents
+//a weekday is its next occurrence including today, next and a weekday is the next occurrence after today
+//dates which do not exist in the calendar, f.e. 2020-02-30, return ErrInvalidDate
+func Parse(value string, now time.Time) (time.Time, error) {
+	input := strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if days, ok := relativeDays[input]; ok {
+		return today.AddDate(0, 0, days), nil
+	}
+
+	if weekday, ok := weekdays[input]; ok {
+		return today.AddDate(0, 0, daysUntil(today.Weekday(), weekday)), nil
+	}
+
+	if match := nextWeekdayRegex.FindStringSubmatch(input); match != nil {
+		if weekday, ok := weekdays[match[1]]; ok {
+			days := daysUntil(today.Weekday(), weekday)
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), nil
+		}
+	}
+
+	if match := relativeDaysRegex.FindStringSubmatch(input); match != nil {
+		number := match[1]
+		if number == "" {
+			number = match[2]
+		}
+		days, err := strconv.Atoi(number)
+		if err == nil {
+			return today.AddDate(0, 0, days), nil
+		}
+	}
+
+	//time.Parse rejects dates which do not exist, f.e. 2020-99-99 or 31.02.2020
+	if date, err := time.ParseInLocation(Layout, input, now.Location()); err == nil {
+		return date, nil
+	}
+	if date, err := time.ParseInLocation(germanLayout, input, now.Location()); err == nil {
+		return date, nil
+	}
+	if match := germanDateWithoutYearRegex.FindStringSubmatch(input); match != nil {
+		if date, err := time.ParseInLocation(germanLayout, match[1]+"."+match[2]+"."+strconv.Itoa(now.Year()), now.Location()); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%q: %w", value, ErrInvalidDate)
+}
+
+//ParseString returns the date described by value relative to the current date in the format YYYY-MM-DD, see Parse
+func ParseString(value string) (string, error) {
+	date, err := Parse(value, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return date.Format(Layout), nil
+}
+
+//ParseRange returns the first and the last date of a range in the format FROM..TO, f.e. 2020-01-27..2020-01-31 or monday..friday
+//FROM is relative to now and TO is relative to FROM, so monday..friday is the next week from monday to friday on any day, see Parse
+func ParseRange(value string, now time.Time) (time.Time, time.Time, error) {
+	parts := strings.Split(value, "..")
+	if len(parts) != 2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("%q: %w", value, ErrInvalidDate)
+	}
+
+	from, err := Parse(parts[0], now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := Parse(parts[1], from)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+//Describe returns "today" or "tomorrow" for these dates relative to now and otherwise the weekday and the date, f.e. "Friday 2020-01-31"
+//date is in the format YYYY-MM-DD, other values are returned unchanged
+func Describe(date string, now time.Time) string {
+	switch date {
+	case now.Format(Layout):
+		return "today"
+	case now.AddDate(0, 0, 1).Format(Layout):
+		return "tomorrow"
+	}
+
+	if day, err := time.Parse(Layout, date); err == nil {
+		return day.Weekday().String() + " " + date
+	}
+	return date
+}
+
+//DescribeDay returns "today", "tomorrow" or "on" with the weekday and the date, f.e. "on Friday 2020-01-31", so it can follow a verb
+func DescribeDay(date string, now time.Time) string {
+	day := Describe(date, now)
+	if day == "today" || day == "tomorrow" {
+		return day
+	}
+	return "on " + day
+}
+
+//daysUntil returns the number of days from one weekday to the next occurrence of another weekday, 0 when they are the same
+func daysUntil(from time.Weekday, to time.Weekday) int {
+	return (int(to) - int(from) + 7) % 7
+}
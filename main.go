@@ -2,22 +2,47 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"gomensa/configutil"
+	"gomensa/dateutil"
 	"gomensa/requests"
+	"io/ioutil"
 	"log"
+	"net"
 	"os"
+	"os/signal"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+)
+
+const (
+	//defaultRadius is the radius in kilometers in which mensas near a location are listed
+	defaultRadius = 10.0
 )
 
 var (
 	anyWhiteSpaceRegex = regexp.MustCompile("\\s+")
+
+	//client is used for all requests to the OpenMensa API
+	client *requests.Client
 )
 
 func main() {
+	//use the API endpoint from the config file, f.e. a self-hosted openmensa mirror
+	config := configutil.ReadConfig()
+	client = newClient(config.APIURL)
+
+	if config.Retries != nil {
+		client.Retry.MaxAttempts = *config.Retries + 1
+	}
+	setRetryDelays(config.RetryBaseDelay, config.RetryMaxDelay)
+	setRateLimit(config.RateLimit)
+
 	//check if program parameters are specified, if not , then start the program in 'interactive mode'
 	if len(os.Args) == 1 {
 		fmt.Println("\t----- GoMensa - your easy mensa helper! -----")
@@ -32,19 +57,38 @@ func printMenu() {
 	fmt.Println("\t-> help")
 	fmt.Println("\t-> quit")
 	fmt.Println("\t-> clear")
-	fmt.Println("\t-> listMensas")
+	fmt.Println("\t-> listMensas [mensaID,mensaID,...]")
+	fmt.Println("\t-> search (query)")
+	fmt.Println("\t-> listCities")
+	fmt.Println("\t-> city (name)")
+	fmt.Println("\t-> near (LAT,LON) [radiusKm]")
 	fmt.Println("\t-> setDefault (mensaID)")
 	fmt.Println("\t-> showMensa [mensaID]")
 	fmt.Println("\t-> mealToday [mensaID]")
 	fmt.Println("\t-> mealTomorrow [mensaID]")
 	fmt.Println("\t-> mealWeek [mensaID]")
-	fmt.Println("\t-> openingStatus [mensaID] [YYYY-MM-DD]")
+	fmt.Println("\t-> mealDate [mensaID] (date)")
+	fmt.Println("\t-> mealRange [mensaID] (date..date)")
+	fmt.Println("\t-> nextOpen [mensaID] [date]")
+	fmt.Println("\t-> openingStatus [mensaID] [date]")
 	fmt.Println("\t values in [] are optional, values in () are needed!")
+	fmt.Println("\t dates can be YYYY-MM-DD, DD.MM.YYYY, today, tomorrow, friday, next friday, +3, in 2 days, heute, morgen, Montag, ...")
 }
 
 //handleProgramLoop is the interactive mode program logic
 // runs until user quits
 func handleProgramLoop() {
+	color, err := useColor(configutil.ReadConfig().Color)
+	if err != nil {
+		log.Println("Could not read the color setting of the config file!", err)
+	}
+
+	//the interactive mode shows everything about the meals, but only the price for students
+	renderer, err := requests.NewRenderer("text", requests.RenderOptions{ShowPrice: true, ShowNotes: true, ShowCategory: true, PriceGroup: requests.PriceStudents, Color: color})
+	if err != nil {
+		log.Fatalln("Could not create the output of the interactive mode!", err)
+	}
+
 	printMenu()
 	var userCommand string = ""
 	input := bufio.NewScanner(os.Stdin)
@@ -57,226 +101,286 @@ func handleProgramLoop() {
 
 		userCommand = input.Text()
 
-		switch {
-		case userCommand == "quit", userCommand == "exit", userCommand == "q":
+		//Ctrl+C cancels the requests of the running command instead of quitting the program
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		quit := handleCommand(ctx, renderer, userCommand)
+		stop()
+
+		if quit {
 			return
-		case userCommand == "help":
-			fmt.Println()
-			printMenu()
-		case userCommand == "clear":
-			fmt.Println("\033[H\033[2J")
-		case userCommand == "listMensas":
-			fmt.Println(requests.CanteenListToString(requests.RequestListOfAllCanteens()))
-		case strings.Contains(userCommand, "setDefault"):
-			splitArr := anyWhiteSpaceRegex.Split(userCommand, -1)
-			if len(splitArr) != 2 {
-				fmt.Println("Could not read the needed mensa ID to set your default mensa!")
+		}
+	}
+}
+
+//handleCommand executes a single command of the interactive mode and writes the results with renderer, returns true when the user wants to quit
+func handleCommand(ctx context.Context, renderer requests.Renderer, userCommand string) bool {
+	switch {
+	case userCommand == "quit", userCommand == "exit", userCommand == "q":
+		return true
+	case userCommand == "help":
+		fmt.Println()
+		printMenu()
+	case userCommand == "clear":
+		fmt.Println("\033[H\033[2J")
+	//the query may contain the names of other commands, so search has to come first
+	case strings.HasPrefix(userCommand, "search"):
+		query := strings.TrimSpace(strings.TrimPrefix(userCommand, "search"))
+		if query == "" {
+			fmt.Println("Invalid format! Please use: search (query), f.e. search leipzig park")
+			break
+		}
+		matches, err := searchCanteens(ctx, query)
+		if err != nil {
+			fmt.Println("Could not search the mensas!", describeError(err))
+			break
+		}
+		renderer.RenderCanteenMatches(os.Stdout, matches)
+	case strings.HasPrefix(userCommand, "city"):
+		city := strings.TrimSpace(strings.TrimPrefix(userCommand, "city"))
+		if city == "" {
+			fmt.Println("Invalid format! Please use: city (name), f.e. city Leipzig")
+			break
+		}
+		canteens, err := requestCanteensInCity(ctx, city)
+		if err != nil {
+			fmt.Println("Could not retrieve the mensas of the city!", describeError(err))
+			break
+		}
+		if len(canteens) == 0 {
+			fmt.Println("There is no mensa in this city, use listCities to see all cities.")
+			break
+		}
+		renderer.RenderCanteens(os.Stdout, canteens)
+	case userCommand == "listCities":
+		canteens, err := client.RequestListOfAllCanteensContext(ctx)
+		if err != nil {
+			fmt.Println("Could not retrieve the list of all cities!", describeError(err))
+			break
+		}
+		renderer.RenderCities(os.Stdout, requests.Cities(canteens))
+	case strings.Contains(userCommand, "listMensas"):
+		splitArr := anyWhiteSpaceRegex.Split(userCommand, -1)
+		if len(splitArr) > 2 {
+			fmt.Println("Invalid format! Please use: listMensas [mensaID,mensaID,...]")
+			break
+		}
+		var options requests.CanteenListOptions
+		if len(splitArr) == 2 {
+			ids, err := parseIDs(splitArr[1])
+			if err != nil {
+				fmt.Println("Could not read the mensa IDs! Please separate them by commas like 31,63")
 				break
 			}
-			mensaID, err := strconv.Atoi(splitArr[1])
+			options.IDs = ids
+		}
+		canteens, err := client.RequestCanteensContext(ctx, options)
+		if err != nil {
+			fmt.Println("Could not retrieve the list of all mensas!", describeError(err))
+			break
+		}
+		renderer.RenderCanteens(os.Stdout, canteens)
+	case strings.Contains(userCommand, "near"):
+		splitArr := anyWhiteSpaceRegex.Split(userCommand, -1)
+		if len(splitArr) < 2 || len(splitArr) > 3 {
+			fmt.Println("Invalid format! Please use: near (LAT,LON) [radiusKm]")
+			break
+		}
+		radius := defaultRadius
+		if len(splitArr) == 3 {
+			var err error
+			radius, err = strconv.ParseFloat(splitArr[2], 64)
 			if err != nil {
-				fmt.Println("Could not read the needed mensa ID to set your default mensa!")
+				fmt.Println("Could not read the radius! Please use a number of kilometers like 2.5")
 				break
 			}
-			if mensaID < 1 {
-				fmt.Println("Please only use a mensaID greater than 0!")
+		}
+		canteens, err := requestCanteensNear(ctx, splitArr[1], radius, requests.CanteenListOptions{})
+		if err != nil {
+			fmt.Println("Could not find the mensas near you!", describeError(err))
+			break
+		}
+		renderer.RenderCanteenDistances(os.Stdout, canteens)
+	case strings.Contains(userCommand, "setDefault"):
+		splitArr := anyWhiteSpaceRegex.Split(userCommand, -1)
+		if len(splitArr) != 2 {
+			fmt.Println("Could not read the needed mensa ID to set your default mensa!")
+			break
+		}
+		mensaID, err := strconv.Atoi(splitArr[1])
+		if err != nil {
+			fmt.Println("Could not read the needed mensa ID to set your default mensa!")
+			break
+		}
+		if mensaID < 1 {
+			fmt.Println("Please only use a mensaID greater than 0!")
+			break
+		}
+		if err := setDefaultCanteen(ctx, mensaID); err != nil {
+			fmt.Println("Could not set your default mensa!", describeError(err))
+		}
+	case strings.Contains(userCommand, "showMensa"):
+		mensa, ok := commandCanteen(ctx, anyWhiteSpaceRegex.Split(userCommand, -1))
+		if ok {
+			renderer.RenderCanteen(os.Stdout, *mensa)
+		}
+	case strings.Contains(userCommand, "mealToday"):
+		mensa, ok := commandCanteen(ctx, anyWhiteSpaceRegex.Split(userCommand, -1))
+		if ok == false {
+			break
+		}
+		date, meals, err := client.RequestCanteenMealOfTodayContext(ctx, uint32(mensa.ID))
+		if err != nil && errors.Is(err, requests.ErrCanteenClosed) == false {
+			fmt.Println("Could not retrieve the meals of today!", describeError(err))
+			break
+		}
+		renderer.RenderMeals(os.Stdout, *mensa, requests.NewMealsOfDay(*date, meals, err))
+	case strings.Contains(userCommand, "mealTomorrow"):
+		mensa, ok := commandCanteen(ctx, anyWhiteSpaceRegex.Split(userCommand, -1))
+		if ok == false {
+			break
+		}
+		date, meals, err := client.RequestCanteenMealOfTomorrowContext(ctx, uint32(mensa.ID))
+		if err != nil && errors.Is(err, requests.ErrCanteenClosed) == false {
+			fmt.Println("Could not retrieve the meals of tomorrow!", describeError(err))
+			break
+		}
+		renderer.RenderMeals(os.Stdout, *mensa, requests.NewMealsOfDay(*date, meals, err))
+	case strings.Contains(userCommand, "mealWeek"):
+		mensa, ok := commandCanteen(ctx, anyWhiteSpaceRegex.Split(userCommand, -1))
+		if ok == false {
+			break
+		}
+		dates, meals, err := client.RequestCanteenMealsOfWeekContext(ctx, uint32(mensa.ID))
+		if err != nil {
+			fmt.Println("Could not retrieve all meals of the week!", describeError(err))
+			//the meals of the other days are still printed
+			if len(dates) == 0 {
 				break
 			}
-			setDefaultCanteen(mensaID)
-		case strings.Contains(userCommand, "showMensa"):
-			splitArr := anyWhiteSpaceRegex.Split(userCommand, -1)
-
-			//user did not specify any ID, try to use default
-			if len(splitArr) == 1 {
-				mensa := configutil.ReadConfig().Canteen
-
-				if mensa.ID != 0 {
-					fmt.Println(requests.CanteenToString(&mensa))
-				} else {
-					fmt.Println("No mensaID was given and there don't seem to be a default mensa.")
-					break
-				}
-			} else {
-				mensaID, err := strconv.Atoi(splitArr[1])
-				if err != nil {
-					fmt.Println("Could not read mensaID! Please use the following format: showMensa [mensaID]. Where mensaID is a normal positive number.")
-					break
-				}
-				if mensaID < 1 {
-					fmt.Println("Please only use a mensaID greater than 0!")
-					break
-				}
-				fmt.Println(requests.CanteenToString(requests.RequestCanteenByID(uint32(mensaID))))
-			}
-
-		case strings.Contains(userCommand, "mealToday"):
-			splitArr := anyWhiteSpaceRegex.Split(userCommand, -1)
-
-			//user did not specify any ID, try to use default
-			if len(splitArr) == 1 {
-				mensa := configutil.ReadConfig().Canteen
-
-				if mensa.ID != 0 {
-					date, meals := requests.RequestCanteenMealOfToday(uint32(mensa.ID))
-					fmt.Println(requests.CanteenMealListToString(*date, meals, &mensa, true, true, true, true, true, true, true))
-				} else {
-					fmt.Println("No mensaID was given and there don't seem to be a default mensa.")
-					break
-				}
-			} else {
-				mensaID, err := strconv.Atoi(splitArr[1])
-				if err != nil {
-					fmt.Println("Could not read mensaID! Please use the following format: showMensa [mensaID]. Where mensaID is a normal positive number.")
-					break
-				}
-				if mensaID < 1 {
-					fmt.Println("Please only use a mensaID greater than 0!")
-					break
-				}
-				mensa := requests.RequestCanteenByID(uint32(mensaID))
-				date, meals := requests.RequestCanteenMealOfToday(uint32(mensaID))
-				fmt.Println(requests.CanteenMealListToString(*date, meals, mensa, true, true, true, true, true, true, true))
-			}
-		case strings.Contains(userCommand, "mealTomorrow"):
-			splitArr := anyWhiteSpaceRegex.Split(userCommand, -1)
-
-			//user did not specify any ID, try to use default
-			if len(splitArr) == 1 {
-				mensa := configutil.ReadConfig().Canteen
-
-				if mensa.ID != 0 {
-					date, meals := requests.RequestCanteenMealOfTomorrow(uint32(mensa.ID))
-					fmt.Println(requests.CanteenMealListToString(*date, meals, &mensa, true, true, true, true, true, true, true))
-				} else {
-					fmt.Println("No mensaID was given and there don't seem to be a default mensa.")
-					break
-				}
-			} else {
-				mensaID, err := strconv.Atoi(splitArr[1])
-				if err != nil {
-					fmt.Println("Could not read mensaID! Please use the following format: showMensa [mensaID]. Where mensaID is a normal positive number.")
-					break
-				}
-				if mensaID < 1 {
-					fmt.Println("Please only use a mensaID greater than 0!")
-					break
-				}
-				mensa := requests.RequestCanteenByID(uint32(mensaID))
-				date, meals := requests.RequestCanteenMealOfTomorrow(uint32(mensaID))
-				fmt.Println(requests.CanteenMealListToString(*date, meals, mensa, true, true, true, true, true, true, true))
+		}
+		renderer.RenderWeek(os.Stdout, *mensa, requests.NewMealsOfDays(dates, meals, err))
+	case strings.Contains(userCommand, "mealDate"):
+		mensaArgs, dateStr := splitDateArgs(anyWhiteSpaceRegex.Split(userCommand, -1), isDate)
+		dateStr, err := dateutil.ParseString(dateStr)
+		if err != nil {
+			fmt.Println("Invalid format! Please use: mealDate [mensaID] (date), f.e. mealDate next friday")
+			break
+		}
+		mensa, ok := commandCanteen(ctx, mensaArgs)
+		if ok == false {
+			break
+		}
+		date, meals, err := client.RequestCanteenMealsOfDateContext(ctx, uint32(mensa.ID), dateStr)
+		if err != nil && errors.Is(err, requests.ErrCanteenClosed) == false {
+			fmt.Println("Could not retrieve the meals of this date!", describeError(err))
+			break
+		}
+		renderer.RenderMeals(os.Stdout, *mensa, requests.NewMealsOfDay(*date, meals, err))
+	case strings.Contains(userCommand, "mealRange"):
+		mensaArgs, rangeStr := splitDateArgs(anyWhiteSpaceRegex.Split(userCommand, -1), isDateRange)
+		from, to, err := parseDateRange(rangeStr)
+		if err != nil {
+			fmt.Println("Invalid format! Please use: mealRange [mensaID] (date..date), f.e. mealRange monday..friday")
+			break
+		}
+		mensa, ok := commandCanteen(ctx, mensaArgs)
+		if ok == false {
+			break
+		}
+		dates, meals, err := client.RequestCanteenMealsForRangeContext(ctx, uint32(mensa.ID), from, to)
+		if err != nil {
+			fmt.Println("Could not retrieve all meals of this range!", describeError(err))
+			//the meals of the other days are still printed
+			if len(dates) == 0 {
+				break
 			}
-		case strings.Contains(userCommand, "mealWeek"):
-			splitArr := anyWhiteSpaceRegex.Split(userCommand, -1)
-
-			//user did not specify any ID, try to use default
-			if len(splitArr) == 1 {
-				mensa := configutil.ReadConfig().Canteen
-
-				if mensa.ID != 0 {
-					dates, meals := requests.RequestCanteenMealsOfWeek(uint32(mensa.ID))
-					fmt.Println(requests.CanteenMealWeekListToString(dates, meals, &mensa, true, true, true, true, true, true, true))
-				} else {
-					fmt.Println("No mensaID was given and there don't seem to be a default mensa.")
-					break
-				}
-			} else {
-				mensaID, err := strconv.Atoi(splitArr[1])
-				if err != nil {
-					fmt.Println("Could not read mensaID! Please use the following format: showMensa [mensaID]. Where mensaID is a normal positive number.")
-					break
-				}
-				if mensaID < 1 {
-					fmt.Println("Please only use a mensaID greater than 0!")
-					break
-				}
-				mensa := requests.RequestCanteenByID(uint32(mensaID))
-				dates, meals := requests.RequestCanteenMealsOfWeek(uint32(mensaID))
-				fmt.Println(requests.CanteenMealWeekListToString(dates, meals, mensa, true, true, true, true, true, true, true))
+		}
+		renderer.RenderWeek(os.Stdout, *mensa, requests.NewMealsOfDays(dates, meals, err))
+	case strings.Contains(userCommand, "nextOpen"):
+		mensaArgs, dateStr := splitDateArgs(anyWhiteSpaceRegex.Split(userCommand, -1), isDate)
+		if dateStr != "" {
+			var err error
+			dateStr, err = dateutil.ParseString(dateStr)
+			if err != nil {
+				fmt.Println("Invalid format! Please use: nextOpen [mensaID] [date], f.e. nextOpen monday")
+				break
 			}
-		case strings.Contains(userCommand, "openingStatus"):
-			splitArr := anyWhiteSpaceRegex.Split(userCommand, -1)
-
-			// user did not specify a date nor a mensaID, use default
-			if len(splitArr) == 1 {
-				mensa := configutil.ReadConfig().Canteen
-
-				if mensa.ID != 0 {
-					date, _ := requests.RequestCanteenDateToday(uint32(mensa.ID))
-					fmt.Println(requests.CanteenDateOpenedToString(date, mensa.Name, false))
-					break
-				} else {
-					fmt.Println("No mensaID was given and there don't seem to be a default mensa.")
-					break
-				}
+		}
+		mensa, ok := commandCanteen(ctx, mensaArgs)
+		if ok == false {
+			break
+		}
+		date, meals, err := client.RequestNextOpenDayContext(ctx, uint32(mensa.ID), dateStr)
+		if err != nil {
+			fmt.Println("Could not find the next open day!", describeError(err))
+			break
+		}
+		fmt.Printf("%s is open %s.\n", mensa.Name, dateutil.DescribeDay(date.Date, time.Now()))
+		renderer.RenderMeals(os.Stdout, *mensa, requests.NewMealsOfDay(*date, meals, nil))
+	case strings.Contains(userCommand, "openingStatus"):
+		mensaArgs, dateStr := splitDateArgs(anyWhiteSpaceRegex.Split(userCommand, -1), isDate)
+		if dateStr != "" {
+			var err error
+			dateStr, err = dateutil.ParseString(dateStr)
+			if err != nil {
+				fmt.Println("Invalid format! Please use: openingStatus [mensaID] [date], f.e. openingStatus morgen")
+				break
 			}
+		}
 
-			//user did not specify any ID or no date, try to use default
-			if len(splitArr) < 3 {
-				dateStr := ""
-				//test if the parameter is the mensaID, if not use the date and use default mensaID
-				mensaID, err := strconv.Atoi(splitArr[1])
-				if err != nil {
-					dateStr = splitArr[1]
-					mensa := configutil.ReadConfig().Canteen
-
-					if mensa.ID != 0 {
-						fmt.Println(dateStr)
-						date, _ := requests.RequestCanteenDate(uint32(mensa.ID), dateStr)
-						fmt.Println(requests.CanteenDateOpenedToString(date, mensa.Name, false))
-						break
-					} else {
-						fmt.Println("No mensaID was given and there don't seem to be a default mensa.")
-						break
-					}
-					//mensaID was given, but no date, so use cantenDate from today
-				} else {
-					if mensaID < 1 {
-						fmt.Println("Please only use a mensaID greater than 0!")
-						break
-					}
-					date, _ := requests.RequestCanteenDateToday(uint32(mensaID))
-					mensa := requests.RequestCanteenByID(uint32(mensaID))
-					fmt.Println(requests.CanteenDateOpenedToString(date, mensa.Name, false))
-					break
-				}
-			}
+		mensa, ok := commandCanteen(ctx, mensaArgs)
+		if ok == false {
+			break
+		}
 
-			//user did specify proper format
-			if len(splitArr) == 3 {
-				mensaID, err := strconv.Atoi(splitArr[1])
-				dateStr := splitArr[2]
-
-				//not valid mensaID
-				if err != nil {
-					mensa := configutil.ReadConfig().Canteen
-
-					if mensa.ID != 0 {
-						date, _ := requests.RequestCanteenDate(uint32(mensa.ID), dateStr)
-						fmt.Println(requests.CanteenDateOpenedToString(date, mensa.Name, false))
-						break
-					} else {
-						fmt.Println("No mensaID was given and there don't seem to be a default mensa.")
-						break
-					}
-					//valid mensaID
-				} else {
-					if mensaID < 1 {
-						fmt.Println("Please only use a mensaID greater than 0!")
-						break
-					}
-					date, _ := requests.RequestCanteenDate(uint32(mensaID), dateStr)
-					mensa := requests.RequestCanteenByID(uint32(mensaID))
-					fmt.Println(requests.CanteenDateOpenedToString(date, mensa.Name, false))
-					break
-				}
-			}
-			fmt.Println("Invalid format! Please use: openingStatus [mensaID] [YYYY-MM-DD]")
-		default:
-			fmt.Println("\nUnknown command :(")
-			printMenu()
+		var date *requests.CanteenDate
+		var err error
+		if dateStr == "" {
+			date, err = client.RequestCanteenDateTodayContext(ctx, uint32(mensa.ID))
+		} else {
+			date, err = client.RequestCanteenDateContext(ctx, uint32(mensa.ID), dateStr)
+		}
+		if err != nil {
+			fmt.Println("Could not retrieve the opening status!", describeError(err))
+			break
 		}
+		renderer.RenderDates(os.Stdout, *mensa, []requests.CanteenDate{*date})
+	default:
+		fmt.Println("\nUnknown command :(")
+		printMenu()
+	}
+	return false
+}
+
+//commandCanteen returns the canteen for the mensaID which is the second word of an interactive command
+//when no mensaID was given the default mensa is used, when no canteen could be determined the user is informed and false is returned
+func commandCanteen(ctx context.Context, splitArr []string) (*requests.Canteen, bool) {
+	//user did not specify any ID, try to use default
+	if len(splitArr) == 1 {
+		mensa := configutil.ReadConfig().Canteen
+		if mensa.ID == 0 {
+			fmt.Println("No mensaID was given and there don't seem to be a default mensa.")
+			return nil, false
+		}
+		return &mensa, true
+	}
+
+	mensaID, err := strconv.Atoi(splitArr[1])
+	if err != nil {
+		fmt.Println("Could not read mensaID! Please use the following format: " + splitArr[0] + " [mensaID]. Where mensaID is a normal positive number.")
+		return nil, false
+	}
+	if mensaID < 1 {
+		fmt.Println("Please only use a mensaID greater than 0!")
+		return nil, false
+	}
 
+	mensa, err := client.RequestCanteenByIDContext(ctx, uint32(mensaID))
+	if err != nil {
+		fmt.Println("Could not retrieve the mensa!", describeError(err))
+		return nil, false
 	}
+	return mensa, true
 }
 
 func handleProgramFlags() {
@@ -289,6 +393,17 @@ func handleProgramFlags() {
 	var printAllCanteens = flag.Bool("listMensas", false, "Advises the program to print all avaible canteens.")
 	flag.BoolVar(printAllCanteens, "lm", false, "See 'listMensas'")
 
+	var search = flag.String("search", "", "Searches the name, city and address of all mensas and prints the best matches with their ID, f.e. 'leipzig park'.")
+
+	var printCities = flag.Bool("listCities", false, "Prints all cities with mensas together with the number of mensas in each city.")
+	var city = flag.String("city", "", "Lists only the mensas in this city, f.e. 'Leipzig'. Use 'listCities' to see all cities.")
+
+	var canteenIDs = flag.String("ids", "", "Only lists the mensas with these IDs separated by commas, f.e. '31,63'. Works together with 'listMensas' and 'near'.")
+	var hasCoordinates = flag.String("hasCoordinates", "", "Set this flag to true to only list the mensas with coordinates or to false to only list the mensas without them. Works together with 'listMensas' and 'ids', with 'near' only true can be used.")
+
+	var nearLocation = flag.String("near", "", "Lists the mensas near the given location in the format LAT,LON sorted by their distance, f.e. '51.3397,12.3731'.")
+	var radius = flag.Float64("radius", defaultRadius, "The radius in kilometers around the location of 'near' in which mensas are listed. 0 lists all mensas.")
+
 	var printMensa = flag.Bool("showMensa", false, "Show basic information about the specified mensa. If no mensa was specified with 'mensaID', then your default mensa is printed.")
 	flag.BoolVar(printMensa, "sm", false, "See 'showMensa'")
 
@@ -322,15 +437,153 @@ func handleProgramFlags() {
 	var showNotes = flag.Bool("notes", false, "Indicates whether some notes about the meals should also be printed.")
 	flag.BoolVar(showNotes, "n", false, "See 'notes'")
 
-	var showMensaDateOpen = flag.String("isOpen", "", "Set this flag to a date value in the format: YYYY-MM-DD and information about the opening status of the mensa is shown.")
+	var mealDate = flag.String("mealDate", "", "Set this flag to a date and you get the meals of this date, f.e. '2020-01-31', '31.01.2020', 'tomorrow', 'next friday', '+3' or 'übermorgen'. This uses the mensaID flag or your default mensa.")
+	var mealRange = flag.String("mealRange", "", "Set this flag to a range of dates in the format: FROM..TO and you get the meals of all days in this range, f.e. '2020-01-27..2020-01-31' or 'monday..friday'. This uses the mensaID flag or your default mensa.")
+
+	var nextOpen = flag.Bool("nextOpen", false, "Prints the next day on which the mensa is open and offers meals together with its meals. This uses the mensaID flag or your default mensa.")
+
+	var showMensaDateOpen = flag.String("isOpen", "", "Set this flag to a date and information about the opening status of the mensa is shown, f.e. '2020-01-31', '31.01.2020', 'tomorrow', 'friday' or 'in 2 days'.")
 	var showMensaWeekOpen = flag.Bool("weekOpen", false, "Shows a list of the next 7 days from your default or specified mensa and if the mensa is opened on these days.")
 
+	var apiURL = flag.String("apiURL", "", "The URL of the OpenMensa API which should be used, f.e. a self-hosted mirror. Defaults to the 'apiURL' value of the config file or https://openmensa.org/api/v2.")
+
+	var offline = flag.Bool("offline", false, "Only use the responses cached in your cache directory and never contact the OpenMensa server. Outdated responses are used as well.")
+	var refresh = flag.Bool("refresh", false, "Ignore the cached responses and request everything from the OpenMensa server again.")
+
+	var retries = flag.Int("retries", -1, "How often a request is repeated after a network error or when the OpenMensa server is overloaded. Defaults to the 'retries' value of the config file or 2.")
+	var retryBaseDelay = flag.Duration("retryBaseDelay", -1, "How long to wait before the first retry, f.e. '500ms' or '0s' to retry immediately. The delay doubles with every further retry. Defaults to the 'retryBaseDelay' value of the config file or 500ms.")
+	var retryMaxDelay = flag.Duration("retryMaxDelay", -1, "The longest wait between two attempts, also when the OpenMensa server asks to wait longer, f.e. '10s'. Defaults to the 'retryMaxDelay' value of the config file or 10s.")
+	var rateLimit = flag.Float64("rateLimit", -1, "Maximal number of requests per second to the OpenMensa server, f.e. 0.5 for one request every two seconds. Defaults to the 'rateLimit' value of the config file, 0 means no limit.")
+	var verbose = flag.Bool("verbose", false, "Log every request, cache usage and retry.")
+	flag.BoolVar(verbose, "v", false, "See 'verbose'")
+
+	var outputFormat = flag.String("output", "text", "The output format: "+strings.Join(requests.Renderers(), ", ")+". json, jsonl and csv contain all information about mensas, dates and meals and are meant for scripts, jq and spreadsheets, markdown and html print tables for wikis and web pages.")
+	flag.StringVar(outputFormat, "o", "text", "See 'output'")
+	var colorMode = flag.String("color", "", "When the output is colored: always, never or auto. auto colors the output when it is printed to a terminal and the NO_COLOR environment variable is not set. Defaults to the 'color' value of the config file or auto.")
+	var templateText = flag.String("template", "", "Prints the output with a Go text/template instead of the output format, f.e. '{{range .Day.Meals}}{{.Name}}: {{price .Prices \"students\"}}\n{{end}}', or the name of a built-in template: "+strings.Join(requests.BuiltinTemplates(), ", ")+".")
+	var templateFile = flag.String("template-file", "", "Like 'template', but reads the template from the file with the given path.")
+	var standalone = flag.Bool("standalone", false, "Prints a complete HTML page with embedded CSS instead of a fragment to embed into another page when used with '--output html'.")
+	var tableOutput = flag.Bool("table", false, "Prints meals, mensas and dates as aligned table, long meal names are wrapped to the width of your terminal. Same as '--output table'.")
+
+	var timeout = flag.Duration("timeout", 0, "Maximum duration of all requests to the OpenMensa API, f.e. '10s' or '1m'. By default only a single request is limited to 30s.")
+
 	flag.Parse()
 
 	if flag.Parsed() == false {
 		log.Fatalln("Something went wrong when trying to parse the command line options! Please call this program with the -help flag to see the correct usage of all support flags!")
 	}
 
+	//when one of the price specifier is set, then the price should also be shown
+	priceGroup := requests.AllPrices
+	switch {
+	case *showOnlyStudent:
+		priceGroup = requests.PriceStudents
+	case *showOnlyEmployees:
+		priceGroup = requests.PriceEmployees
+	case *showOnlyOther:
+		priceGroup = requests.PriceOthers
+	case *showOnlyPupils:
+		priceGroup = requests.PricePupils
+	}
+
+	if *tableOutput {
+		*outputFormat = "table"
+	}
+
+	if *colorMode == "" {
+		*colorMode = configutil.ReadConfig().Color
+	}
+	color, err := useColor(*colorMode)
+	if err != nil {
+		log.Fatalln("Could not read the color mode! Please use always, never or auto.", err)
+	}
+
+	renderOptions := requests.RenderOptions{
+		ShowPrice:    *showPrice || priceGroup != requests.AllPrices,
+		ShowNotes:    *showNotes,
+		ShowCategory: *showCategory,
+		PriceGroup:   priceGroup,
+		Width:        terminalWidth(),
+		Color:        color,
+		Standalone:   *standalone,
+	}
+
+	var renderer requests.Renderer
+	switch {
+	case *templateText != "" && *templateFile != "":
+		log.Fatalln("Please use either 'template' or 'template-file'!")
+	case *templateText != "", *templateFile != "":
+		renderer, err = newTemplateRenderer(*templateText, *templateFile, renderOptions)
+		if err != nil {
+			log.Fatalln("Could not read the template!", err)
+		}
+	default:
+		renderer, err = requests.NewRenderer(*outputFormat, renderOptions)
+		if err != nil {
+			log.Fatalln("Could not read the output format!", err)
+		}
+	}
+
+	if *apiURL != "" {
+		client.BaseURL = *apiURL
+	}
+
+	if *retries >= 0 {
+		client.Retry.MaxAttempts = *retries + 1
+	}
+	if *retryBaseDelay >= 0 {
+		client.Retry.BaseDelay = *retryBaseDelay
+	}
+	if *retryMaxDelay >= 0 {
+		client.Retry.MaxDelay = *retryMaxDelay
+	}
+
+	if *rateLimit >= 0 {
+		setRateLimit(*rateLimit)
+	}
+
+	if *verbose {
+		client.Logger = log.New(os.Stderr, "gomensa: ", log.LstdFlags)
+	}
+
+	if *offline && *refresh {
+		log.Fatalln("Can't use 'offline' and 'refresh' together, because refreshing needs the OpenMensa server!")
+	}
+	if *offline || *refresh {
+		if client.Cache == nil {
+			log.Fatalln("Can't use 'offline' or 'refresh' because your cache directory could not be determined!")
+		}
+		client.Cache.Offline = *offline
+		client.Cache.Refresh = *refresh
+	}
+
+	//Ctrl+C cancels all running requests
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	var options requests.CanteenListOptions
+	if *canteenIDs != "" {
+		var err error
+		options.IDs, err = parseIDs(*canteenIDs)
+		if err != nil {
+			log.Fatalln("Could not read the mensa IDs of 'ids'! Please separate them by commas like 31,63")
+		}
+	}
+	if *hasCoordinates != "" {
+		withCoordinates, err := strconv.ParseBool(*hasCoordinates)
+		if err != nil {
+			log.Fatalln("Could not read 'hasCoordinates'! Please use true or false.")
+		}
+		options.HasCoordinates = &withCoordinates
+	}
+	filtered := len(options.IDs) > 0 || options.HasCoordinates != nil
+
 	canteenID := -1
 	var canteen *requests.Canteen = &requests.Canteen{}
 
@@ -344,77 +597,398 @@ func handleProgramFlags() {
 		canteen = &configutil.ReadConfig().Canteen
 		//canteenID is always the n 0 after reading from config, when the config did not exist previously
 		if canteenID == 0 {
-			// sepcial case: no IDs were set/ saved, but the user wants to list all mensas, then we dont need a special mensa ID
-			if *printAllCanteens == false {
+			// sepcial case: no IDs were set/ saved, but the user wants to list all or nearby mensas, then we dont need a special mensa ID
+			if *printAllCanteens == false && *nearLocation == "" && filtered == false && *search == "" && *printCities == false && *city == "" {
 				log.Fatalln("No mensaID was given and no defaultID exist in the config files! Please set either one of them!")
 			}
 		}
 	} else {
+		var err error
 		canteenID = *canteenIDParam
-		canteen = requests.RequestCanteenByID(uint32(canteenID))
-	}
-
-	//when one of the price specifier is set, then the showPrice value should also be true
-	if *showOnlyStudent || *showOnlyEmployees || *showOnlyOther || *showOnlyPupils {
-		*showPrice = true
+		canteen, err = client.RequestCanteenByIDContext(ctx, uint32(canteenID))
+		if err != nil {
+			log.Fatalln("Could not retrieve the mensa with the given mensaID!", describeError(err))
+		}
 	}
 
 	switch {
-	case *printAllCanteens == true:
-		fmt.Println(requests.CanteenListToString(requests.RequestListOfAllCanteens()))
+	case *search != "":
+		matches, err := searchCanteens(ctx, *search)
+		if err != nil {
+			log.Fatalln("Could not search the mensas!", describeError(err))
+		}
+		writeOutput(renderer.RenderCanteenMatches(os.Stdout, matches))
+
+	case *printCities == true:
+		canteens, err := client.RequestListOfAllCanteensContext(ctx)
+		if err != nil {
+			log.Fatalln("Could not retrieve the list of all cities!", describeError(err))
+		}
+		writeOutput(renderer.RenderCities(os.Stdout, requests.Cities(canteens)))
+
+	case *city != "":
+		canteens, err := requestCanteensInCity(ctx, *city)
+		if err != nil {
+			log.Fatalln("Could not retrieve the mensas of the city!", describeError(err))
+		}
+		if len(canteens) == 0 {
+			log.Fatalln("There is no mensa in this city, use 'listCities' to see all cities.")
+		}
+		writeOutput(renderer.RenderCanteens(os.Stdout, canteens))
+
+	case *nearLocation != "":
+		canteens, err := requestCanteensNear(ctx, *nearLocation, *radius, options)
+		if err != nil {
+			log.Fatalln("Could not find the mensas near the given location!", describeError(err))
+		}
+		writeOutput(renderer.RenderCanteenDistances(os.Stdout, canteens))
+
+	case *printAllCanteens == true, filtered:
+		canteens, err := client.RequestCanteensContext(ctx, options)
+		if err != nil {
+			log.Fatalln("Could not retrieve the list of all mensas!", describeError(err))
+		}
+		writeOutput(renderer.RenderCanteens(os.Stdout, canteens))
 
 	case *printMensa == true:
-		fmt.Println(requests.CanteenToString(canteen))
+		writeOutput(renderer.RenderCanteen(os.Stdout, *canteen))
 
 	case *getTodayMeal == true:
-		date, meals := requests.RequestCanteenMealOfToday(uint32(canteenID))
-		fmt.Println(requests.CanteenMealListToString(*date, meals, canteen, *showPrice, *showNotes, *showCategory, *showOnlyStudent, *showOnlyEmployees, *showOnlyOther, *showOnlyPupils))
+		date, meals, err := client.RequestCanteenMealOfTodayContext(ctx, uint32(canteenID))
+		if err != nil && errors.Is(err, requests.ErrCanteenClosed) == false {
+			log.Fatalln("Could not retrieve the meals of today!", describeError(err))
+		}
+		writeOutput(renderer.RenderMeals(os.Stdout, *canteen, requests.NewMealsOfDay(*date, meals, err)))
 
 	case *getTomorrowMeal == true:
-		date, meal := requests.RequestCanteenMealOfTomorrow(uint32(canteenID))
-		fmt.Println(requests.CanteenMealListToString(*date, meal, canteen, *showPrice, *showNotes, *showCategory, *showOnlyStudent, *showOnlyEmployees, *showOnlyOther, *showOnlyPupils))
+		date, meals, err := client.RequestCanteenMealOfTomorrowContext(ctx, uint32(canteenID))
+		if err != nil && errors.Is(err, requests.ErrCanteenClosed) == false {
+			log.Fatalln("Could not retrieve the meals of tomorrow!", describeError(err))
+		}
+		writeOutput(renderer.RenderMeals(os.Stdout, *canteen, requests.NewMealsOfDay(*date, meals, err)))
 
 	case *getWeekMeal == true:
-		canteenWeek, canteenMealWeek := requests.RequestCanteenMealsOfWeek(uint32(canteenID))
-		fmt.Println(requests.CanteenMealWeekListToString(canteenWeek, canteenMealWeek, canteen, *showPrice, *showNotes, *showCategory, *showOnlyStudent, *showOnlyEmployees, *showOnlyOther, *showOnlyPupils))
+		canteenWeek, canteenMealWeek, err := client.RequestCanteenMealsOfWeekContext(ctx, uint32(canteenID))
+		if err != nil {
+			//the meals of the other days are still printed
+			if len(canteenWeek) == 0 {
+				log.Fatalln("Could not retrieve the meals of the week!", describeError(err))
+			}
+			log.Println("Could not retrieve all meals of the week!", describeError(err))
+		}
+		writeOutput(renderer.RenderWeek(os.Stdout, *canteen, requests.NewMealsOfDays(canteenWeek, canteenMealWeek, err)))
+
+	case *mealDate != "":
+		dateStr, err := dateutil.ParseString(*mealDate)
+		if err != nil {
+			log.Fatalln("Could not read the date of 'mealDate'!", describeError(err))
+		}
+		date, meals, err := client.RequestCanteenMealsOfDateContext(ctx, uint32(canteenID), dateStr)
+		if err != nil && errors.Is(err, requests.ErrCanteenClosed) == false {
+			log.Fatalln("Could not retrieve the meals of this date!", describeError(err))
+		}
+		writeOutput(renderer.RenderMeals(os.Stdout, *canteen, requests.NewMealsOfDay(*date, meals, err)))
+
+	case *mealRange != "":
+		from, to, err := parseDateRange(*mealRange)
+		if err != nil {
+			log.Fatalln("Could not read the range of 'mealRange'! Please use the format FROM..TO like monday..friday.", describeError(err))
+		}
+		dates, meals, err := client.RequestCanteenMealsForRangeContext(ctx, uint32(canteenID), from, to)
+		if err != nil {
+			//the meals of the other days are still printed
+			if len(dates) == 0 {
+				log.Fatalln("Could not retrieve the meals of this range!", describeError(err))
+			}
+			log.Println("Could not retrieve all meals of this range!", describeError(err))
+		}
+		writeOutput(renderer.RenderWeek(os.Stdout, *canteen, requests.NewMealsOfDays(dates, meals, err)))
+
+	case *nextOpen == true:
+		date, meals, err := client.RequestNextOpenDayContext(ctx, uint32(canteenID), "")
+		if err != nil {
+			log.Fatalln("Could not find the next open day!", describeError(err))
+		}
+		writeOutput(renderer.RenderMeals(os.Stdout, *canteen, requests.NewMealsOfDay(*date, meals, nil)))
 
 	case *defaultCanteen > 0:
-		setDefaultCanteen(*defaultCanteen)
+		if err := setDefaultCanteen(ctx, *defaultCanteen); err != nil {
+			log.Fatalln("Could not set your default mensa!", describeError(err))
+		}
 
-	case len(*showMensaDateOpen) > 1:
-		date, ok := requests.RequestCanteenDate(uint32(canteenID), *showMensaDateOpen)
-		if ok == false {
-			fmt.Println("Could not retrieve a date for the given mensa ID, also check if the date string is correct!")
-		} else {
-			fmt.Println(requests.CanteenDateOpenedToString(date, canteen.Name, false))
+	case *showMensaDateOpen != "":
+		dateStr, err := dateutil.ParseString(*showMensaDateOpen)
+		if err != nil {
+			log.Fatalln("Could not read the date of 'isOpen'!", describeError(err))
+		}
+		date, err := client.RequestCanteenDateContext(ctx, uint32(canteenID), dateStr)
+		if err != nil {
+			log.Fatalln("Could not retrieve a date for the given mensa ID, also check if the date string is correct!", describeError(err))
 		}
+		writeOutput(renderer.RenderDates(os.Stdout, *canteen, []requests.CanteenDate{*date}))
 
 	case *showMensaWeekOpen == true:
-		week, ok := requests.RequestCanteenWeek(uint32(canteenID))
-
-		if ok == false {
-			fmt.Println("Could not retrieve information about the next 7 days of your mensa! Maybe check if the mensa ID is correct...")
-		} else {
-			fmt.Println(requests.CanteenDateListToString(week, canteen.Name))
+		week, err := client.RequestCanteenWeekContext(ctx, uint32(canteenID))
+		if err != nil {
+			log.Fatalln("Could not retrieve information about the next 7 days of your mensa! Maybe check if the mensa ID is correct...", describeError(err))
 		}
+		writeOutput(renderer.RenderDates(os.Stdout, *canteen, week))
 
 	default:
 		log.Println("Did not specify any flag! Doing nothing.")
 	}
 }
 
-func setDefaultCanteen(canteenID int) {
-	canteen := requests.RequestCanteenByID(uint32(canteenID))
+//writeOutput stops the program when the output could not be written
+func writeOutput(err error) {
+	if err != nil {
+		log.Fatalln("Could not write the output!", err)
+	}
+}
+
+//newTemplateRenderer returns a renderer for the template text or, when the text is empty, for the template in the file at path
+func newTemplateRenderer(text string, path string, options requests.RenderOptions) (requests.Renderer, error) {
+	if text == "" {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		text = string(content)
+	}
+	return requests.NewTemplateRenderer(text, options)
+}
+
+//useColor reports whether the output should be colored in the given color mode, f.e. auto
+func useColor(mode string) (bool, error) {
+	colorMode, err := requests.ParseColorMode(mode)
+	if err != nil {
+		return false, err
+	}
+	return requests.UseColor(colorMode, os.Stdout), nil
+}
+
+//terminalWidth returns the number of columns of the terminal from the COLUMNS environment variable, 0 when it is not known
+func terminalWidth() int {
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || width < 0 {
+		return 0
+	}
+	return width
+}
 
-	if canteen == nil {
-		log.Fatalln("Could not set default canteen because seems that a mensa with this ID does not exist!")
+//newClient returns a client for the given OpenMensa API URL which caches the responses in the users cache directory
+func newClient(apiURL string) *requests.Client {
+	apiClient := requests.NewClient(apiURL)
+	if cacheDir, err := requests.DefaultCacheDir(); err == nil {
+		apiClient.Cache = requests.NewCache(cacheDir)
+	}
+	return apiClient
+}
+
+//requestCanteensNear returns the mensas within radius kilometers of the location in the format LAT,LON, the nearest mensa first
+//the API only sends the mensas in the radius, the other filters of options like the IDs are applied as well
+func requestCanteensNear(ctx context.Context, location string, radius float64, options requests.CanteenListOptions) ([]requests.CanteenDistance, error) {
+	//the radius of 0 does not send the location to the API, so this is not rejected by the request
+	if options.HasCoordinates != nil && *options.HasCoordinates == false {
+		return nil, requests.ErrNearWithoutCoordinates
+	}
+	coordinates, err := requests.ParseCoordinates(location)
+	if err != nil {
+		return nil, err
 	}
-	config := configutil.Config{Canteen: *canteen}
-	ok := configutil.SaveConfig(&config)
+
+	//a radius of 0 lists all mensas, so the whole list is needed
+	if radius > 0 {
+		options.Near = &coordinates
+		options.NearDistance = radius
+	}
+
+	canteens, err := client.RequestCanteensContext(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return requests.CanteensNear(canteens, coordinates, radius), nil
+}
+
+//searchCanteens searches the list of all mensas, see requests.SearchCanteens
+func searchCanteens(ctx context.Context, query string) ([]requests.CanteenMatch, error) {
+	canteens, err := client.RequestListOfAllCanteensContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return requests.SearchCanteens(canteens, query), nil
+}
+
+//requestCanteensInCity returns the mensas in the given city, see requests.CanteensInCity
+func requestCanteensInCity(ctx context.Context, city string) ([]requests.Canteen, error) {
+	canteens, err := client.RequestListOfAllCanteensContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return requests.CanteensInCity(canteens, city), nil
+}
+
+//splitDateArgs splits the parameters of an interactive command into the mensa parameters for commandCanteen and the date, the first element is the command itself
+//a date can consist of multiple words, f.e. "next friday" or "in 2 days", so the mensa ID is only split off when the parameters are no date themselves
+//then either the first or the last parameter is the mensa ID
+func splitDateArgs(splitArr []string, isDate func(string) bool) ([]string, string) {
+	args := splitArr[1:]
+	if len(args) == 0 || (len(args) == 1 && args[0] == "") {
+		return splitArr[:1], ""
+	}
+
+	dateStr := strings.Join(args, " ")
+	if isDate(dateStr) {
+		return splitArr[:1], dateStr
+	}
+
+	if _, err := strconv.Atoi(args[0]); err == nil {
+		return []string{splitArr[0], args[0]}, strings.Join(args[1:], " ")
+	}
+	if _, err := strconv.Atoi(args[len(args)-1]); err == nil {
+		return []string{splitArr[0], args[len(args)-1]}, strings.Join(args[:len(args)-1], " ")
+	}
+	return splitArr[:1], dateStr
+}
+
+//isDate reports whether value is a date understood by dateutil.Parse
+func isDate(value string) bool {
+	_, err := dateutil.ParseString(value)
+	return err == nil
+}
+
+//isDateRange reports whether value is a range of dates understood by parseDateRange
+func isDateRange(value string) bool {
+	_, _, err := parseDateRange(value)
+	return err == nil
+}
+
+//parseDateRange parses a range of dates in the format FROM..TO, f.e. 2020-01-06..2020-01-10 or monday..friday, and returns both dates in the format YYYY-MM-DD
+//TO is relative to FROM, see dateutil.ParseRange
+func parseDateRange(value string) (string, string, error) {
+	from, to, err := dateutil.ParseRange(value, time.Now())
+	if err != nil {
+		return "", "", err
+	}
+	return from.Format(dateutil.Layout), to.Format(dateutil.Layout), nil
+}
+
+//parseIDs parses a comma separated list of mensa IDs, f.e. 31,63
+func parseIDs(value string) ([]int, error) {
+	var ids []int
+	for _, field := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid mensa ID %d", id)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+//setRetryDelays sets the delays of the retry policy from the config file, f.e. 500ms or 10s, empty or invalid values keep the defaults
+func setRetryDelays(baseDelay string, maxDelay string) {
+	if delay, ok := parseConfigDelay("retryBaseDelay", baseDelay); ok {
+		client.Retry.BaseDelay = delay
+	}
+	if delay, ok := parseConfigDelay("retryMaxDelay", maxDelay); ok {
+		client.Retry.MaxDelay = delay
+	}
+}
+
+//parseConfigDelay parses a duration of the config file with the given name, it reports false when the value is empty or invalid
+func parseConfigDelay(name string, value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil || delay < 0 {
+		log.Printf("Could not read '%s' of the config file! Please use a duration like 500ms or 10s.\n", name)
+		return 0, false
+	}
+	return delay, true
+}
+
+//setRateLimit limits the requests of the client to perSecond requests per second, a value of 0 removes the limit
+func setRateLimit(perSecond float64) {
+	if perSecond <= 0 {
+		client.RateLimiter = nil
+		return
+	}
+	//allow as many requests at once as the limit per second, but at least one
+	client.RateLimiter = requests.NewRateLimiter(perSecond, int(perSecond))
+}
+
+//setDefaultCanteen requests the canteen with the given ID and saves it as the default canteen in the config file
+func setDefaultCanteen(ctx context.Context, canteenID int) error {
+	canteen, err := client.RequestCanteenByIDContext(ctx, uint32(canteenID))
+	if err != nil {
+		return err
+	}
+
+	//keep the other settings of the existing config
+	config := configutil.ReadConfig()
+	config.Canteen = *canteen
+	ok := configutil.SaveConfig(config)
 
 	if ok == false {
-		log.Fatalln("Something went wrong when trying to set your default mensa and save it to the configuration file!")
-	} else {
-		fmt.Println("Successfully saved your default mensa!")
+		return errors.New("Something went wrong when trying to save it to the configuration file!")
+	}
+
+	fmt.Println("Successfully saved your default mensa!")
+	return nil
+}
+
+//describeError returns a message for the user explaining why a request failed
+//when the meals of multiple days failed, every day is described on its own line
+func describeError(err error) string {
+	var mealsErr *requests.MealsError
+	if errors.As(err, &mealsErr) {
+		builder := strings.Builder{}
+		for _, day := range mealsErr.Days {
+			builder.WriteString(fmt.Sprintf("\n - %s: %s", day.Date, describeError(day.Err)))
+		}
+		return builder.String()
+	}
+
+	var closedErr *requests.ClosedError
+	if errors.As(err, &closedErr) {
+		return requests.ClosedToString(requests.MealsOfDay{Date: closedErr.Date, Closed: true, NextOpenDate: closedErr.NextOpenDate})
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "The request was cancelled."
+	case errors.Is(err, context.DeadlineExceeded):
+		return "The OpenMensa server did not answer in time, maybe increase the 'timeout'."
+	case errors.Is(err, requests.ErrCanteenNotFound):
+		return "There is no mensa with this ID."
+	case errors.Is(err, requests.ErrNoDataForDate):
+		return "The mensa did not publish any information for this date."
+	case errors.Is(err, dateutil.ErrInvalidDate):
+		return "The date is invalid, please use f.e. YYYY-MM-DD, DD.MM.YYYY, today, tomorrow, friday, next friday, +3 or in 2 days."
+	case errors.Is(err, requests.ErrInvalidDate):
+		return "The date is invalid, please use the format YYYY-MM-DD."
+	case errors.Is(err, requests.ErrInvalidDateRange):
+		return "The end of the range is before its start."
+	case errors.Is(err, requests.ErrInvalidCoordinates):
+		return "The location is invalid, please use the format LAT,LON like 51.3397,12.3731."
+	case errors.Is(err, requests.ErrNearWithoutCoordinates):
+		return "Mensas without coordinates can not be near a location, use 'hasCoordinates' only with true together with 'near'."
+	case errors.Is(err, requests.ErrNotCached):
+		return "You are offline and this information is not cached yet."
+	case errors.Is(err, requests.ErrRateLimited):
+		return "OpenMensa received too many requests, please try again later."
+	case errors.Is(err, requests.ErrServer):
+		return "The OpenMensa server has problems right now, please try again later. (" + err.Error() + ")"
+	case errors.Is(err, requests.ErrInvalidResponse):
+		return "The OpenMensa server sent an unexpected answer, maybe check the 'apiURL'. (" + err.Error() + ")"
+	case errors.As(err, &netErr):
+		return "Could not reach the OpenMensa server, please check your internet connection. (" + err.Error() + ")"
 	}
+	return err.Error()
 }
@@ -0,0 +1,212 @@
+package requests
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	//DefaultCanteenTTL is how long cached canteens and canteen lists are used before they are requested again
+	DefaultCanteenTTL = 7 * 24 * time.Hour
+	//DefaultDayTTL is how long cached opening days are used before they are requested again
+	DefaultDayTTL = 6 * time.Hour
+	//DefaultMealTTL is how long cached meal plans are used before they are requested again
+	DefaultMealTTL = 3 * time.Hour
+
+	//cacheDirName is the name of the gomensa folder inside of the users cache directory
+	cacheDirName = "gomensa"
+
+	//rwPermissionCachePath permission bits for the cache folders
+	rwPermissionCachePath = 0700
+	//rwPermissionCacheFile permission bits for the cache files
+	rwPermissionCacheFile = 0600
+)
+
+var (
+	//ErrNotCached is returned in offline mode when a response is not cached yet
+	ErrNotCached = errors.New("response is not cached and offline mode is enabled")
+
+	//unsafeFileNameRegex matches all characters which should not be part of a cache file name
+	unsafeFileNameRegex = regexp.MustCompile("[^A-Za-z0-9.=-]+")
+)
+
+//Cache stores the responses of the OpenMensa API on disk, so they can be reused without making a request
+type Cache struct {
+	//Dir is the directory of the cache files
+	Dir string
+	//Offline only uses cached responses, also expired ones, and never makes a request
+	Offline bool
+	//Refresh ignores cached responses and always makes a request, the new responses are still stored
+	Refresh bool
+
+	//CanteenTTL is how long a cached canteen or page of the canteen list is used
+	CanteenTTL time.Duration
+	//DayTTL is how long cached opening days of a canteen are used, they are never used after the day they were stored
+	DayTTL time.Duration
+	//MealTTL is how long cached meals of a day are used, they are never used after the day they were stored
+	MealTTL time.Duration
+}
+
+//cacheEntry is a single cached response, it is saved as a json file
+type cacheEntry struct {
+	URL      string            `json:"url"`
+	StoredAt time.Time         `json:"storedAt"`
+	Header   map[string]string `json:"header"`
+	Body     string            `json:"body"`
+}
+
+//cachedHeaders are the response headers which are stored together with the body
+var cachedHeaders = []string{"Content-Type", "X-Total-Pages", "ETag", "Last-Modified"}
+
+//DefaultCacheDir returns the gomensa folder inside of the users cache directory, f.e. ~/.cache/gomensa
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, cacheDirName), nil
+}
+
+//NewCache returns a cache which stores its files in dir and uses the default TTLs
+func NewCache(dir string) *Cache {
+	return &Cache{
+		Dir:        dir,
+		CanteenTTL: DefaultCanteenTTL,
+		DayTTL:     DefaultDayTTL,
+		MealTTL:    DefaultMealTTL,
+	}
+}
+
+//load returns the cached response for the URL and whether it is still fresh, the entry is nil when there is no cached response
+func (c *Cache) load(requestURL *url.URL) (*cacheEntry, bool) {
+	content, err := ioutil.ReadFile(c.fileName(requestURL))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if json.Unmarshal(content, &entry) != nil || entry.URL != requestURL.String() {
+		return nil, false
+	}
+
+	return &entry, c.isFresh(requestURL.Path, entry.StoredAt, time.Now())
+}
+
+//store saves a response in the cache, the file is replaced atomically so concurrent readers never see a partial entry
+func (c *Cache) store(requestURL *url.URL, body []byte, header http.Header) error {
+	entry := cacheEntry{
+		URL:      requestURL.String(),
+		StoredAt: time.Now(),
+		Header:   map[string]string{},
+		Body:     string(body),
+	}
+	for _, key := range cachedHeaders {
+		if value := header.Get(key); value != "" {
+			entry.Header[key] = value
+		}
+	}
+
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	fileName := c.fileName(requestURL)
+	if err := os.MkdirAll(filepath.Dir(fileName), rwPermissionCachePath); err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(fileName), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpFile.Name(), rwPermissionCacheFile); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), fileName)
+}
+
+//fileName returns the cache file of an URL, f.e. ~/.cache/gomensa/openmensa.org/api-v2-canteens-63-days.json
+//every API host gets its own folder, the path and the query make up the name of the file
+func (c *Cache) fileName(requestURL *url.URL) string {
+	name := strings.Trim(requestURL.Path, "/")
+	if requestURL.RawQuery != "" {
+		name += "_" + requestURL.RawQuery
+	}
+	name = unsafeFileNameRegex.ReplaceAllString(name, "-")
+	host := unsafeFileNameRegex.ReplaceAllString(requestURL.Host, "-")
+	return filepath.Join(c.Dir, host, name+".json")
+}
+
+//isFresh reports whether an entry of the given API path stored at storedAt can still be used at now
+func (c *Cache) isFresh(path string, storedAt time.Time, now time.Time) bool {
+	age := now.Sub(storedAt)
+
+	switch {
+	case strings.HasSuffix(path, "/meals"):
+		return age < c.MealTTL && sameDay(storedAt, now)
+	case strings.HasSuffix(path, "/days") || strings.Contains(path, "/days/"):
+		//days are requested relative to the current date, so yesterdays answers are wrong today
+		return age < c.DayTTL && sameDay(storedAt, now)
+	default:
+		return age < c.CanteenTTL
+	}
+}
+
+//header returns the cached headers of the entry as http.Header
+func (e *cacheEntry) header() http.Header {
+	header := http.Header{}
+	for key, value := range e.Header {
+		header.Set(key, value)
+	}
+	return header
+}
+
+//validators returns the headers of a conditional request which revalidates the entry, f.e. If-None-Match with the stored ETag
+//a nil entry or an entry without ETag and Last-Modified returns no headers
+func (e *cacheEntry) validators() http.Header {
+	header := http.Header{}
+	if e == nil {
+		return header
+	}
+	if etag := e.Header["ETag"]; etag != "" {
+		header.Set("If-None-Match", etag)
+	}
+	if lastModified := e.Header["Last-Modified"]; lastModified != "" {
+		header.Set("If-Modified-Since", lastModified)
+	}
+	return header
+}
+
+//revalidated updates the validators of the entry with the headers of a 304 Not Modified response
+func (e *cacheEntry) revalidated(header http.Header) {
+	for _, key := range []string{"ETag", "Last-Modified"} {
+		if value := header.Get(key); value != "" {
+			e.Header[key] = value
+		}
+	}
+}
+
+//sameDay reports whether a and b are on the same local calendar day
+func sameDay(a time.Time, b time.Time) bool {
+	aYear, aMonth, aDay := a.Local().Date()
+	bYear, bMonth, bDay := b.Local().Date()
+	return aYear == bYear && aMonth == bMonth && aDay == bDay
+}
@@ -1,139 +1,178 @@
 package requests
 
 import (
-	"encoding/json"
-	"io/ioutil"
-	"log"
-	"net/http"
+	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
 )
 
-const (
-	openMensaEndpoint = "https://openmensa.org/api/v2"
-)
+//Canteen is a struct representing a single canteen instance, the coordinates are nil when the API does not know them
+type Canteen struct {
+	ID          int          `json:"id"`
+	Name        string       `json:"name"`
+	City        string       `json:"city"`
+	Address     string       `json:"address"`
+	Coordinates *Coordinates `json:"coordinates,omitempty"`
+}
 
-// abort handles the abortion when requesting all available canteens
-var abort = make(chan struct{})
+//RequestCanteenByID makes a get request for retrieving a single Canteen by its ID using the DefaultClient
+func RequestCanteenByID(ID uint32) (*Canteen, error) {
+	return DefaultClient.RequestCanteenByID(ID)
+}
 
-// sema shall limit the number of goroutines for requesting all available canteens
-var sema = make(chan struct{}, 5)
+//RequestListOfAllCanteens request all canteens from all api pages using the DefaultClient and return a list of all
+func RequestListOfAllCanteens() ([]Canteen, error) {
+	return DefaultClient.RequestListOfAllCanteens()
+}
 
-//Canteen is a struct representing a single canteen instance without geopgrapical coordinates
-type Canteen struct {
-	ID      int    `json:"id"`
-	Name    string `json:"name"`
-	City    string `json:"city"`
-	Address string `json:"address"`
+//RequestCanteens requests all canteens matching the options from all api pages using the DefaultClient
+func RequestCanteens(options CanteenListOptions) ([]Canteen, error) {
+	return DefaultClient.RequestCanteens(options)
 }
 
-//RequestCanteenByID makes a get request for retrieving a single Canteen by its ID
-func RequestCanteenByID(ID uint32) *Canteen {
-	baseURL, err := url.Parse(openMensaEndpoint)
-	if err != nil {
-		log.Println("ERROR: Malformed URL ", err.Error())
-		return nil
+//CanteenListOptions filters the canteens on the server when requesting a list of canteens, the zero value requests all canteens
+type CanteenListOptions struct {
+	//IDs only requests the canteens with these IDs
+	IDs []int
+	//Near only requests the canteens within NearDistance kilometers of this location
+	Near *Coordinates
+	//NearDistance is the radius in kilometers around Near, a value of 0 uses the default of the API which is 10 km
+	NearDistance float64
+	//HasCoordinates only requests canteens with coordinates when true or without coordinates when false, nil requests both
+	//false together with Near returns ErrNearWithoutCoordinates, because a canteen without coordinates is never near a location
+	HasCoordinates *bool
+}
+
+//params returns the options as query parameters of the OpenMensa API
+func (o CanteenListOptions) params() url.Values {
+	params := url.Values{}
+
+	if len(o.IDs) > 0 {
+		ids := make([]string, len(o.IDs))
+		for i, id := range o.IDs {
+			ids[i] = strconv.Itoa(id)
+		}
+		params.Add("ids", strings.Join(ids, ","))
 	}
 
-	// Add a Path Segment (Path segment is automatically escaped)
-	baseURL.Path += "/canteens/" + strconv.Itoa(int(ID))
+	if o.Near != nil {
+		params.Add("near[lat]", strconv.FormatFloat(o.Near.Latitude, 'f', -1, 64))
+		params.Add("near[lng]", strconv.FormatFloat(o.Near.Longitude, 'f', -1, 64))
+		if o.NearDistance > 0 {
+			params.Add("near[dist]", strconv.FormatFloat(o.NearDistance, 'f', -1, 64))
+		}
+	}
 
-	resp, err := http.Get(baseURL.String())
-	if err != nil {
-		log.Println("ERROR: Something went wrong when requesting a list of all canteens!", err.Error())
-		return nil
+	if o.HasCoordinates != nil {
+		params.Add("hasCoordinates", strconv.FormatBool(*o.HasCoordinates))
 	}
+	return params
+}
 
-	defer resp.Body.Close()
+//RequestCanteenByID makes a get request for retrieving a single Canteen by its ID
+func (c *Client) RequestCanteenByID(ID uint32) (*Canteen, error) {
+	return c.RequestCanteenByIDContext(context.Background(), ID)
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+//RequestCanteenByIDContext makes a get request for retrieving a single Canteen by its ID, the request is aborted when ctx is cancelled
+//returns ErrCanteenNotFound when the API does not know a canteen with this ID
+func (c *Client) RequestCanteenByIDContext(ctx context.Context, ID uint32) (*Canteen, error) {
+	body, _, err := c.get(ctx, "/canteens/"+strconv.Itoa(int(ID)), nil)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("requesting canteen %d: %w", ID, ErrCanteenNotFound)
+	}
 	if err != nil {
-		log.Println("ERROR: Something went wrong when processing the result of requesting a list of all canteens!", err.Error())
-		return nil
+		return nil, fmt.Errorf("requesting canteen %d: %w", ID, err)
 	}
 
 	var canteen Canteen
-	err = json.Unmarshal(body, &canteen)
+	err = decodeJSON(body, &canteen)
 	if err != nil {
-		log.Println("ERROR: Something went wrong when trying to parse the requestcanteen result!", err.Error())
-		return nil
+		return nil, fmt.Errorf("parsing canteen %d: %w", ID, err)
+	}
+
+	//a response without an ID does not describe a canteen
+	if canteen.ID == 0 {
+		return nil, fmt.Errorf("requesting canteen %d: %w", ID, ErrCanteenNotFound)
 	}
 
-	return &canteen
+	return &canteen, nil
 }
 
 //RequestListOfAllCanteens request all canteens from all api pages and return a list of all
-func RequestListOfAllCanteens() []Canteen {
-	//currently there are more than 400 canteens, so we can allocate some memory before appending the slices
-	allCanteens := make([]Canteen, 0, 400)
+func (c *Client) RequestListOfAllCanteens() ([]Canteen, error) {
+	return c.RequestListOfAllCanteensContext(context.Background())
+}
 
-	canteensChan := make(chan []Canteen)
-	page := 1
+//RequestListOfAllCanteensContext request all canteens from all api pages and return a list of all, the requests are aborted when ctx is cancelled
+func (c *Client) RequestListOfAllCanteensContext(ctx context.Context) ([]Canteen, error) {
+	return c.RequestCanteensContext(ctx, CanteenListOptions{})
+}
 
-loop:
-	for {
-		select {
-		case sema <- struct{}{}: //acquire token
-		case <-abort:
-			break loop
-		}
+//RequestCanteens requests all canteens matching the options from all api pages, the filtering is done by the API
+func (c *Client) RequestCanteens(options CanteenListOptions) ([]Canteen, error) {
+	return c.RequestCanteensContext(context.Background(), options)
+}
 
-		go requestCanteens(page, canteensChan)
-		page++
+//RequestCanteensContext requests all canteens matching the options from all api pages, the requests are aborted when ctx is cancelled
+//the first page tells how many pages exist, the remaining pages are requested concurrently by at most Parallelism workers
+//the order of the canteens is the same as in the API, when a page fails the first error is returned and all other requests are cancelled
+func (c *Client) RequestCanteensContext(ctx context.Context, options CanteenListOptions) ([]Canteen, error) {
+	if options.Near != nil && options.HasCoordinates != nil && *options.HasCoordinates == false {
+		return nil, ErrNearWithoutCoordinates
+	}
+	params := options.params()
 
-		value, ok := <-canteensChan
-		//request and parse responses until the channel is closed -> this happens when we reach the max page number
-		if ok == false {
-			break
-		}
-		allCanteens = append(allCanteens, value...)
+	firstPage, totalPages, err := c.requestCanteens(ctx, params, 1)
+	if err != nil {
+		return nil, err
 	}
-	return allCanteens
-}
 
-//requestCanteens makes a GET request to the openmensa endpoint and returns a list of all canteens
-func requestCanteens(page int, canteensChan chan<- []Canteen) {
-	defer func() { <-sema }()
-	baseURL, err := url.Parse(openMensaEndpoint)
+	pages := make([][]Canteen, totalPages)
+	pages[0] = firstPage
+
+	//the first page is already known, so the workers request the pages from 2 on
+	err = forEach(ctx, totalPages-1, c.parallelism(), func(ctx context.Context, i int) error {
+		canteens, _, err := c.requestCanteens(ctx, params, i+2)
+		if err != nil {
+			return err
+		}
+		pages[i+1] = canteens
+		return nil
+	})
 	if err != nil {
-		log.Println("ERROR: Malformed URL ", err.Error())
-		abort <- struct{}{}
-		return
+		return nil, err
 	}
 
-	// Add a Path Segment (Path segment is automatically escaped)
-	baseURL.Path += "/canteens"
+	//currently there are more than 400 canteens, so we can allocate some memory before appending the slices
+	allCanteens := make([]Canteen, 0, 400)
+	for _, canteens := range pages {
+		allCanteens = append(allCanteens, canteens...)
+	}
+	return allCanteens, nil
+}
 
+//requestCanteens makes a GET request to the openmensa endpoint and returns the canteens of the given page together with the total number of pages
+//filter are the query parameters of the CanteenListOptions, they are sent together with the page
+func (c *Client) requestCanteens(ctx context.Context, filter url.Values, page int) ([]Canteen, int, error) {
 	// Prepare Query Parameters
 	params := url.Values{}
-	params.Add("page", strconv.Itoa(page))
-
-	// Add Query Parameters to the URL
-	baseURL.RawQuery = params.Encode()
-
-	resp, err := http.Get(baseURL.String())
-	if err != nil {
-		log.Println("ERROR: Something went wrong when requesting a list of all canteens!", err.Error())
-		abort <- struct{}{}
-		return
+	for key, values := range filter {
+		params[key] = values
 	}
+	params.Set("page", strconv.Itoa(page))
 
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
+	body, header, err := c.get(ctx, "/canteens", params)
 	if err != nil {
-		log.Println("ERROR: Something went wrong when processing the result of requesting a list of all canteens!", err.Error())
-		abort <- struct{}{}
-		return
+		return nil, 0, fmt.Errorf("requesting page %d of all canteens: %w", page, err)
 	}
 
 	var canteens []Canteen
-	err = json.Unmarshal(body, &canteens)
+	err = decodeJSON(body, &canteens)
 	if err != nil {
-		log.Println("ERROR: Something went wrong when trying to parse the canteen list results!", err.Error())
-		abort <- struct{}{}
-		return
+		return nil, 0, fmt.Errorf("parsing page %d of all canteens: %w", page, err)
 	}
 
 	//cleaning random new lines
@@ -143,18 +182,14 @@ func requestCanteens(page int, canteensChan chan<- []Canteen) {
 		canteens[i].Address = strings.ReplaceAll(canteens[i].Address, "\n", "")
 	}
 
-	canteensChan <- canteens
-
-	// check if the next page would be the last page and then closes the channel
-	maxPages, err := strconv.Atoi(resp.Header.Get("X-Total-Pages"))
-	if err != nil {
-		log.Println("ERROR: Could not convert max page header key to int!")
-		abort <- struct{}{}
-		return
+	//without the header the API did not paginate the result
+	totalPages := 1
+	if totalPagesHeader := header.Get("X-Total-Pages"); totalPagesHeader != "" {
+		totalPages, err = strconv.Atoi(totalPagesHeader)
+		if err != nil || totalPages < 1 {
+			return nil, 0, fmt.Errorf("%w: invalid X-Total-Pages header %q", ErrInvalidResponse, totalPagesHeader)
+		}
 	}
 
-	if page+1 > maxPages {
-		abort <- struct{}{}
-		return
-	}
+	return canteens, totalPages, nil
 }
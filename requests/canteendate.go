@@ -1,25 +1,22 @@
 package requests
 
 import (
-	"encoding/json"
-	"io/ioutil"
-	"log"
-	"net/http"
+	"context"
+	"fmt"
 	"net/url"
-	"regexp"
 	"strconv"
+	"time"
 )
 
 const (
-	dateMatchingString = "\\d\\d\\d\\d-\\d\\d-\\d\\d"
-
 	pageFlag      = 1
 	limitFlag     = 2
 	startDateFlag = 4
-)
 
-var (
-	dateMatchingRegex = regexp.MustCompile(dateMatchingString)
+	//dateLayout is the format of all dates of the OpenMensa API
+	dateLayout = "2006-01-02"
+	//daysPageLimit is the number of days requested per page when requesting a range of days
+	daysPageLimit = 50
 )
 
 //CanteenDate is a struct representing a date of a single canteen and if the canteen is closed at this date
@@ -28,50 +25,209 @@ type CanteenDate struct {
 	Closed bool   `json:"closed"`
 }
 
+//RequestCanteenDateTomorrow returns the canteen date of tomorrow using the DefaultClient
+func RequestCanteenDateTomorrow(ID uint32) (*CanteenDate, error) {
+	return DefaultClient.RequestCanteenDateTomorrow(ID)
+}
+
+//RequestCanteenDateToday returns the current date as a canteen date using the DefaultClient
+func RequestCanteenDateToday(ID uint32) (*CanteenDate, error) {
+	return DefaultClient.RequestCanteenDateToday(ID)
+}
+
+//RequestCanteenDate returns the canteen date for the given date in the format YYYY-MM-DD using the DefaultClient
+func RequestCanteenDate(ID uint32, date string) (*CanteenDate, error) {
+	return DefaultClient.RequestCanteenDate(ID, date)
+}
+
+//RequestCanteenWeek returns the next 7 days of a canteen using the DefaultClient
+func RequestCanteenWeek(ID uint32) ([]CanteenDate, error) {
+	return DefaultClient.RequestCanteenWeek(ID)
+}
+
+//RequestCanteenDatesForRange returns the known days of a canteen from one date to another using the DefaultClient
+func RequestCanteenDatesForRange(ID uint32, from string, to string) ([]CanteenDate, error) {
+	return DefaultClient.RequestCanteenDatesForRange(ID, from, to)
+}
+
 //RequestCanteenDateTomorrow calls the requestDatesOfCanteen function with the limit = 1, a page = 2 and no startDate, so we retrieve the canteen date of tomorrow
-func RequestCanteenDateTomorrow(ID uint32) (*CanteenDate, bool) {
-	canteenDay := requestDatesOfCanteen(ID, "", 2, 1)
-	if canteenDay == nil || len(canteenDay) == 0 {
-		return &CanteenDate{}, false
+func (c *Client) RequestCanteenDateTomorrow(ID uint32) (*CanteenDate, error) {
+	return c.RequestCanteenDateTomorrowContext(context.Background(), ID)
+}
+
+//RequestCanteenDateTomorrowContext returns the canteen date of tomorrow, the request is aborted when ctx is cancelled
+func (c *Client) RequestCanteenDateTomorrowContext(ctx context.Context, ID uint32) (*CanteenDate, error) {
+	canteenDay, err := c.requestDatesOfCanteen(ctx, ID, "", 2, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(canteenDay) == 0 {
+		return nil, fmt.Errorf("requesting date of tomorrow of canteen %d: %w", ID, ErrNoDataForDate)
 	}
-	return &canteenDay[0], true
+	return &canteenDay[0], nil
 }
 
 //RequestCanteenDateToday calls the requestDatesOfCanteen function with the limit of 1 and no startDate, so we retrieve the current date as a canteen date
-func RequestCanteenDateToday(ID uint32) (*CanteenDate, bool) {
-	canteenDay := requestDatesOfCanteen(ID, "", 0, 1)
-	if canteenDay == nil || len(canteenDay) == 0 {
-		return &CanteenDate{}, false
+func (c *Client) RequestCanteenDateToday(ID uint32) (*CanteenDate, error) {
+	return c.RequestCanteenDateTodayContext(context.Background(), ID)
+}
+
+//RequestCanteenDateTodayContext returns the current date as a canteen date, the request is aborted when ctx is cancelled
+func (c *Client) RequestCanteenDateTodayContext(ctx context.Context, ID uint32) (*CanteenDate, error) {
+	canteenDay, err := c.requestDatesOfCanteen(ctx, ID, "", 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(canteenDay) == 0 {
+		return nil, fmt.Errorf("requesting date of today of canteen %d: %w", ID, ErrNoDataForDate)
 	}
-	return &canteenDay[0], true
+	return &canteenDay[0], nil
 }
 
 //RequestCanteenDate given the ID and a date in the format YYYY-MM-DD the an instance of canteendate is returned with information about whether or not the canteen is opened on this day
-func RequestCanteenDate(ID uint32, date string) (*CanteenDate, bool) {
-	canteenDate := requestDatesOfCanteen(ID, date, 0, 1)
+func (c *Client) RequestCanteenDate(ID uint32, date string) (*CanteenDate, error) {
+	return c.RequestCanteenDateContext(context.Background(), ID, date)
+}
 
-	if canteenDate == nil || len(canteenDate) == 0 {
-		return &CanteenDate{}, false
+//RequestCanteenDateContext returns the canteen date of the given date in the format YYYY-MM-DD, the request is aborted when ctx is cancelled
+//returns ErrNoDataForDate when the API has no information about this date
+func (c *Client) RequestCanteenDateContext(ctx context.Context, ID uint32, date string) (*CanteenDate, error) {
+	canteenDate, err := c.requestDatesOfCanteen(ctx, ID, date, 0, 1)
+	if err != nil {
+		return nil, err
 	}
 
-	return &canteenDate[0], true
+	//the API returns the next known date when there is no information about the requested one
+	if len(canteenDate) == 0 || canteenDate[0].Date != date {
+		return nil, fmt.Errorf("requesting date %s of canteen %d: %w", date, ID, ErrNoDataForDate)
+	}
+
+	return &canteenDate[0], nil
 }
 
 //RequestCanteenWeek calls the requestDatesOfCanteen function with the limit of 7 and no startDate, so we retrieve the next 7 days of a canteen
-func RequestCanteenWeek(ID uint32) ([]CanteenDate, bool) {
-	canteenWeek := requestDatesOfCanteen(ID, "", 0, 7)
-	//when a nil slice was passed, it is most likely an error occured, so we return a false ok value
-	if canteenWeek == nil || len(canteenWeek) == 0 {
-		return []CanteenDate{}, false
+func (c *Client) RequestCanteenWeek(ID uint32) ([]CanteenDate, error) {
+	return c.RequestCanteenWeekContext(context.Background(), ID)
+}
+
+//RequestCanteenWeekContext returns the next 7 days of a canteen, the request is aborted when ctx is cancelled
+func (c *Client) RequestCanteenWeekContext(ctx context.Context, ID uint32) ([]CanteenDate, error) {
+	canteenWeek, err := c.requestDatesOfCanteen(ctx, ID, "", 0, 7)
+	if err != nil {
+		return nil, err
+	}
+	if len(canteenWeek) == 0 {
+		return nil, fmt.Errorf("requesting week of canteen %d: %w", ID, ErrNoDataForDate)
+	}
+	return canteenWeek, nil
+}
+
+//RequestCanteenDatesForRange returns the days of a canteen from the date from to the date to, both included and in the format YYYY-MM-DD
+//only days the API has information about are returned, so the list can have gaps or be empty
+func (c *Client) RequestCanteenDatesForRange(ID uint32, from string, to string) ([]CanteenDate, error) {
+	return c.RequestCanteenDatesForRangeContext(context.Background(), ID, from, to)
+}
+
+//RequestCanteenDatesForRangeContext returns the days of a canteen from the date from to the date to, the requests are aborted when ctx is cancelled
+//the days are requested page by page starting at from until the API has no more days or the date to is reached
+func (c *Client) RequestCanteenDatesForRangeContext(ctx context.Context, ID uint32, from string, to string) ([]CanteenDate, error) {
+	if err := validateDateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	days, err := requestDayRange(from, to, c.datePages(ctx, ID, from))
+	if err != nil {
+		return nil, err
 	}
-	return canteenWeek, true
+
+	dates := make([]CanteenDate, len(days))
+	for i, day := range days {
+		dates[i] = day.CanteenDate
+	}
+	return dates, nil
+}
+
+//datePages returns a function requesting a page of the days of a canteen from the date from for forEachDay, the days have no meals
+func (c *Client) datePages(ctx context.Context, ID uint32, from string) func(page uint32) ([]mealDay, error) {
+	return func(page uint32) ([]mealDay, error) {
+		dates, err := c.requestDatesOfCanteen(ctx, ID, from, page, daysPageLimit)
+		days := make([]mealDay, len(dates))
+		for i, date := range dates {
+			days[i].CanteenDate = date
+		}
+		return days, err
+	}
+}
+
+//forEachDay requests days page by page with requestPage, the first page has the number 1, and calls fn for every day in the order of the API
+//the pages are requested until the API has no more days, fn returns false or an error, or the API ignores the paging
+func forEachDay(requestPage func(page uint32) ([]mealDay, error), fn func(day mealDay) (bool, error)) error {
+	lastDate := ""
+	for page := uint32(1); ; page++ {
+		pageDays, err := requestPage(page)
+		if err != nil {
+			return err
+		}
+
+		for _, day := range pageDays {
+			//a page which does not continue after the previous one means the API ignores the paging
+			//dates have the format YYYY-MM-DD, so they can be compared as strings
+			if day.Date <= lastDate {
+				return nil
+			}
+			lastDate = day.Date
+
+			next, err := fn(day)
+			if err != nil || next == false {
+				return err
+			}
+		}
+
+		if len(pageDays) < daysPageLimit {
+			return nil
+		}
+	}
+}
+
+//requestDayRange requests the days from the date from to the date to page by page with requestPage, see forEachDay
+func requestDayRange(from string, to string, requestPage func(page uint32) ([]mealDay, error)) ([]mealDay, error) {
+	days := []mealDay{}
+	err := forEachDay(requestPage, func(day mealDay) (bool, error) {
+		if day.Date > to {
+			return false, nil
+		}
+		if day.Date >= from {
+			days = append(days, day)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+//validateDateRange checks that from and to are dates in the format YYYY-MM-DD and that from is not after to
+func validateDateRange(from string, to string) error {
+	fromDate, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return fmt.Errorf("%q: %w", from, ErrInvalidDate)
+	}
+	toDate, err := time.Parse(dateLayout, to)
+	if err != nil {
+		return fmt.Errorf("%q: %w", to, ErrInvalidDate)
+	}
+	if toDate.Before(fromDate) {
+		return fmt.Errorf("%s is before %s: %w", to, from, ErrInvalidDateRange)
+	}
+	return nil
 }
 
 //requestDatesOfCanteen requests Dates of canteens returning a list of CanteenDate for representing open/ closed dates of the canteen
 //it is advised to expect that the returned list of dates can be empty, this is the case when to date information is given
-//this function needs an ID, a startDate in the form YYYY-MM-DD for specifiyng a startDate for requesting, when passing an unvalid format or empty string the current date is used
+//this function needs an ID, a startDate in the form YYYY-MM-DD for specifiyng a startDate for requesting, when passing an empty string the current date is used and an unvalid format returns ErrInvalidDate
 //also a page and a maximal limit of returns can be specified if you dont need them set them to 0
-func requestDatesOfCanteen(ID uint32, startDate string, page uint32, limit uint32) []CanteenDate {
+func (c *Client) requestDatesOfCanteen(ctx context.Context, ID uint32, startDate string, page uint32, limit uint32) ([]CanteenDate, error) {
 
 	//useFlags is flag representing which parameters to use for requesting the canteendate
 	usageFlags := uint8(0)
@@ -85,23 +241,13 @@ func requestDatesOfCanteen(ID uint32, startDate string, page uint32, limit uint3
 	}
 
 	if len(startDate) > 0 {
-		//only set startDateFlag when the date is valid!
-		if dateMatchingRegex.MatchString(startDate) == false {
-			log.Println("ERROR: The passed startDate does not follow the following structure: YYYY-MM-DD! Using current date.")
-		} else {
-			usageFlags |= startDateFlag
+		//only set startDateFlag when the date is valid, time.Parse also rejects dates which do not exist like 2020-99-99
+		if _, err := time.Parse(dateLayout, startDate); err != nil {
+			return nil, fmt.Errorf("%q: %w", startDate, ErrInvalidDate)
 		}
+		usageFlags |= startDateFlag
 	}
 
-	baseURL, err := url.Parse(openMensaEndpoint)
-	if err != nil {
-		log.Println("ERROR: Malformed URL ", err.Error())
-		return nil
-	}
-
-	// Add a Path Segment (Path segment is automatically escaped)
-	baseURL.Path += "/canteens/" + strconv.Itoa(int(ID)) + "/days"
-
 	// Prepare Query Parameters
 	params := url.Values{}
 
@@ -120,28 +266,18 @@ func requestDatesOfCanteen(ID uint32, startDate string, page uint32, limit uint3
 		params.Add("start", startDate)
 	}
 
-	// Add Query Parameters to the URL
-	baseURL.RawQuery = params.Encode()
-
-	resp, err := http.Get(baseURL.String())
-	if err != nil {
-		log.Println("ERROR: Something went wrong when requesting a list of canteenDates!", err.Error())
-		return nil
+	body, _, err := c.get(ctx, "/canteens/"+strconv.Itoa(int(ID))+"/days", params)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("requesting dates of canteen %d: %w", ID, ErrCanteenNotFound)
 	}
-
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("ERROR: Something went wrong when processing the result of requesting a list of canteenDates!", err.Error())
-		return nil
+		return nil, fmt.Errorf("requesting dates of canteen %d: %w", ID, err)
 	}
 
 	var canteenDates []CanteenDate
-	err = json.Unmarshal(body, &canteenDates)
+	err = decodeJSON(body, &canteenDates)
 	if err != nil {
-		log.Println("ERROR: Something went wrong when trying to parse the canteen list results!", err.Error())
-		return nil
+		return nil, fmt.Errorf("parsing dates of canteen %d: %w", ID, err)
 	}
-	return canteenDates
+	return canteenDates, nil
 }
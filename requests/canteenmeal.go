@@ -1,12 +1,13 @@
 package requests
 
 import (
-	"encoding/json"
-	"io/ioutil"
-	"log"
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 //CanteenMeal is a struct representing a single meal of a canteen
@@ -18,6 +19,12 @@ type CanteenMeal struct {
 	Category string   `json:"category"`
 }
 
+//mealDay is a day of a canteen together with its meals like the /canteens/{id}/meals endpoint returns it
+type mealDay struct {
+	CanteenDate
+	Meals []CanteenMeal `json:"meals"`
+}
+
 type prices struct {
 	Students  float64 `json:"students"`
 	Employees float64 `json:"employees"`
@@ -25,95 +32,353 @@ type prices struct {
 	Others    float64 `json:"others"`
 }
 
+//RequestCanteenMealOfTomorrow returns all meals that are offered at the given canteen tomorrow using the DefaultClient
+func RequestCanteenMealOfTomorrow(canteenID uint32) (*CanteenDate, []CanteenMeal, error) {
+	return DefaultClient.RequestCanteenMealOfTomorrow(canteenID)
+}
+
+//RequestCanteenMealsOfWeek returns all meals of the next 7 days from a given canteen using the DefaultClient
+func RequestCanteenMealsOfWeek(canteenID uint32) ([]CanteenDate, [][]CanteenMeal, error) {
+	return DefaultClient.RequestCanteenMealsOfWeek(canteenID)
+}
+
+//RequestCanteenMealsOfDate returns all meals of a canteen at the given date in the format YYYY-MM-DD using the DefaultClient
+func RequestCanteenMealsOfDate(canteenID uint32, date string) (*CanteenDate, []CanteenMeal, error) {
+	return DefaultClient.RequestCanteenMealsOfDate(canteenID, date)
+}
+
+//RequestCanteenMealsForRange returns all meals of a canteen from one date to another using the DefaultClient
+func RequestCanteenMealsForRange(canteenID uint32, from string, to string) ([]CanteenDate, [][]CanteenMeal, error) {
+	return DefaultClient.RequestCanteenMealsForRange(canteenID, from, to)
+}
+
+//RequestNextOpenDay returns the first day from the date from on which the canteen is open and offers meals using the DefaultClient
+func RequestNextOpenDay(canteenID uint32, from string) (*CanteenDate, []CanteenMeal, error) {
+	return DefaultClient.RequestNextOpenDay(canteenID, from)
+}
+
+//RequestCanteenMealOfToday returns the canteenMeal for the current day using the DefaultClient
+func RequestCanteenMealOfToday(canteenID uint32) (*CanteenDate, []CanteenMeal, error) {
+	return DefaultClient.RequestCanteenMealOfToday(canteenID)
+}
+
 //RequestCanteenMealOfTomorrow returns all meals that are offered at the given canteen tomorrow
-func RequestCanteenMealOfTomorrow(canteenID uint32) (*CanteenDate, []CanteenMeal) {
-	canteenDateToday, ok := RequestCanteenDateTomorrow(canteenID)
+func (c *Client) RequestCanteenMealOfTomorrow(canteenID uint32) (*CanteenDate, []CanteenMeal, error) {
+	return c.RequestCanteenMealOfTomorrowContext(context.Background(), canteenID)
+}
 
-	if canteenDateToday.Date == "" || ok == false {
-		log.Println("Something went wrong when trying to request mensa date of tomorrow!")
-		return &CanteenDate{}, []CanteenMeal{}
+//RequestCanteenMealOfTomorrowContext returns all meals that are offered at the given canteen tomorrow, the requests are aborted when ctx is cancelled
+//when the canteen is closed on this day, the date is returned together with a *ClosedError which knows the next open day
+func (c *Client) RequestCanteenMealOfTomorrowContext(ctx context.Context, canteenID uint32) (*CanteenDate, []CanteenMeal, error) {
+	canteenDateTomorrow, err := c.RequestCanteenDateTomorrowContext(ctx, canteenID)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	canteenMeals := requestCanteenMeals(canteenID, canteenDateToday.Date)
-	if canteenMeals == nil {
-		return &CanteenDate{}, []CanteenMeal{}
+	if canteenDateTomorrow.Closed {
+		return canteenDateTomorrow, nil, c.closedError(ctx, canteenID, canteenDateTomorrow.Date)
 	}
-	return canteenDateToday, canteenMeals
+
+	canteenMeals, err := c.requestCanteenMeals(ctx, canteenID, canteenDateTomorrow.Date)
+	if err != nil {
+		return nil, nil, err
+	}
+	return canteenDateTomorrow, canteenMeals, nil
 }
 
 //RequestCanteenMealsOfWeek returns all meals of the next 7 days from a given canteen
-func RequestCanteenMealsOfWeek(canteenID uint32) ([]CanteenDate, [][]CanteenMeal) {
-	canteenDateList, ok := RequestCanteenWeek(canteenID)
+func (c *Client) RequestCanteenMealsOfWeek(canteenID uint32) ([]CanteenDate, [][]CanteenMeal, error) {
+	return c.RequestCanteenMealsOfWeekContext(context.Background(), canteenID)
+}
 
-	if len(canteenDateList) == 0 || ok == false {
-		log.Println("Something went wrong when trying to request mensa dates of week!")
-		return []CanteenDate{}, [][]CanteenMeal{}
+//RequestCanteenMealsOfWeekContext returns all meals of the next 7 days from a given canteen, the requests are aborted when ctx is cancelled
+//all days are requested at once, APIs without the /canteens/{id}/meals endpoint are asked for the meals of every day
+//when the meals of some days could not be requested, all dates and the meals of the other days are returned together with a *MealsError
+func (c *Client) RequestCanteenMealsOfWeekContext(ctx context.Context, canteenID uint32) ([]CanteenDate, [][]CanteenMeal, error) {
+	days, err := c.requestMealDays(ctx, canteenID, "", 0, 7)
+	if err == nil {
+		//not every API respects the limit
+		if len(days) > 7 {
+			days = days[:7]
+		}
+		if len(days) == 0 {
+			return nil, nil, fmt.Errorf("requesting week of canteen %d: %w", canteenID, ErrNoDataForDate)
+		}
+		canteenDateList, canteenMealList := splitMealDays(days)
+		return canteenDateList, canteenMealList, nil
+	}
+	if bulkUnsupported(err) == false {
+		return nil, nil, err
 	}
+	c.logf("requesting all meals of the week at once failed: %v, requesting every day", err)
 
-	canteenMealList := make([][]CanteenMeal, len(canteenDateList))
-	mealList := []CanteenMeal{}
+	canteenDateList, err := c.RequestCanteenWeekContext(ctx, canteenID)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	for i, date := range canteenDateList {
-		mealList = requestCanteenMeals(canteenID, date.Date)
+	canteenMealList, err := c.requestMealsOfDates(ctx, canteenID, canteenDateList)
+	return canteenDateList, canteenMealList, err
+}
 
-		if mealList == nil {
-			canteenMealList[i] = nil
-			break
-		}
-		canteenMealList[i] = mealList
+//RequestCanteenMealsOfDate returns all meals of a canteen at the given date in the format YYYY-MM-DD
+func (c *Client) RequestCanteenMealsOfDate(canteenID uint32, date string) (*CanteenDate, []CanteenMeal, error) {
+	return c.RequestCanteenMealsOfDateContext(context.Background(), canteenID, date)
+}
+
+//RequestCanteenMealsOfDateContext returns all meals of a canteen at the given date in the format YYYY-MM-DD, the requests are aborted when ctx is cancelled
+//returns ErrNoDataForDate when the API has no information about this date
+//when the canteen is closed on this day, the date is returned together with a *ClosedError which knows the next open day
+func (c *Client) RequestCanteenMealsOfDateContext(ctx context.Context, canteenID uint32, date string) (*CanteenDate, []CanteenMeal, error) {
+	canteenDate, err := c.RequestCanteenDateContext(ctx, canteenID, date)
+	if err != nil {
+		return nil, nil, err
 	}
-	return canteenDateList, canteenMealList
+
+	if canteenDate.Closed {
+		return canteenDate, nil, c.closedError(ctx, canteenID, canteenDate.Date)
+	}
+
+	canteenMeals, err := c.requestCanteenMeals(ctx, canteenID, canteenDate.Date)
+	if err != nil {
+		return nil, nil, err
+	}
+	return canteenDate, canteenMeals, nil
 }
 
-//RequestCanteenMealOfToday returns the canteenMeal for the current day
-//this functions makes a requestCanteenDate request to see if the canteen is open and if there is any information provided about the meals
-func RequestCanteenMealOfToday(canteenID uint32) (*CanteenDate, []CanteenMeal) {
-	canteenDateToday, ok := RequestCanteenDateToday(canteenID)
-	//check if we retrieved an empty instance of the canteenDate
-	if canteenDateToday.Date == "" || ok == false {
-		log.Println("Something went wrong when trying to request mensa date of today!")
-		return &CanteenDate{}, []CanteenMeal{}
+//RequestCanteenMealsForRange returns all meals of a canteen from the date from to the date to, both included and in the format YYYY-MM-DD
+func (c *Client) RequestCanteenMealsForRange(canteenID uint32, from string, to string) ([]CanteenDate, [][]CanteenMeal, error) {
+	return c.RequestCanteenMealsForRangeContext(context.Background(), canteenID, from, to)
+}
+
+//RequestCanteenMealsForRangeContext returns all meals of a canteen from the date from to the date to, the requests are aborted when ctx is cancelled
+//only days the API has information about are returned, returns ErrNoDataForDate when there is no such day in the range
+//the days are requested page by page, APIs without the /canteens/{id}/meals endpoint are asked for the meals of every day
+//when the meals of some days could not be requested, all dates and the meals of the other days are returned together with a *MealsError
+func (c *Client) RequestCanteenMealsForRangeContext(ctx context.Context, canteenID uint32, from string, to string) ([]CanteenDate, [][]CanteenMeal, error) {
+	if err := validateDateRange(from, to); err != nil {
+		return nil, nil, err
 	}
 
-	canteenMeals := requestCanteenMeals(canteenID, canteenDateToday.Date)
-	if canteenMeals == nil {
-		log.Println("Something went wrong when trying to request mensa meal of today!")
-		return &CanteenDate{}, []CanteenMeal{}
+	var canteenDateList []CanteenDate
+	var canteenMealList [][]CanteenMeal
+
+	days, err := requestDayRange(from, to, func(page uint32) ([]mealDay, error) {
+		return c.requestMealDays(ctx, canteenID, from, page, daysPageLimit)
+	})
+	switch {
+	case err == nil:
+		canteenDateList, canteenMealList = splitMealDays(days)
+	case bulkUnsupported(err):
+		c.logf("requesting all meals from %s to %s at once failed: %v, requesting every day", from, to, err)
+		canteenDateList, err = c.RequestCanteenDatesForRangeContext(ctx, canteenID, from, to)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(canteenDateList) > 0 {
+			canteenMealList, err = c.requestMealsOfDates(ctx, canteenID, canteenDateList)
+		}
+	default:
+		return nil, nil, err
 	}
-	return canteenDateToday, canteenMeals
+
+	if len(canteenDateList) == 0 {
+		return nil, nil, fmt.Errorf("requesting dates %s to %s of canteen %d: %w", from, to, canteenID, ErrNoDataForDate)
+	}
+	return canteenDateList, canteenMealList, err
 }
 
-//requestCanteenMeals is a function which requests a list of meals for a given canteen with a canteenID and a canteenDate
-func requestCanteenMeals(canteendID uint32, canteenDate string) []CanteenMeal {
-	baseURL, err := url.Parse(openMensaEndpoint)
+//RequestNextOpenDay returns the first day from the date from in the format YYYY-MM-DD on which the canteen is open and offers meals, together with the meals
+//when from is empty the search starts today
+func (c *Client) RequestNextOpenDay(canteenID uint32, from string) (*CanteenDate, []CanteenMeal, error) {
+	return c.RequestNextOpenDayContext(context.Background(), canteenID, from)
+}
+
+//RequestNextOpenDayContext returns the first day from the date from on which the canteen is open and offers meals, the requests are aborted when ctx is cancelled
+//the days are requested page by page until an open day with meals is found, returns ErrNoDataForDate when the API knows no such day
+func (c *Client) RequestNextOpenDayContext(ctx context.Context, canteenID uint32, from string) (*CanteenDate, []CanteenMeal, error) {
+	if from == "" {
+		from = time.Now().Format(dateLayout)
+	}
+
+	var openDate *CanteenDate
+	var openMeals []CanteenMeal
+	err := forEachDay(c.datePages(ctx, canteenID, from), func(day mealDay) (bool, error) {
+		if day.Closed || day.Date < from {
+			return true, nil
+		}
+
+		meals, err := c.requestCanteenMeals(ctx, canteenID, day.Date)
+		//open days without a published menu are skipped
+		if errors.Is(err, ErrNoDataForDate) || (err == nil && len(meals) == 0) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		date := day.CanteenDate
+		openDate, openMeals = &date, meals
+		return false, nil
+	})
 	if err != nil {
-		log.Println("ERROR: Malformed URL ", err.Error())
+		return nil, nil, err
+	}
+	if openDate == nil {
+		return nil, nil, fmt.Errorf("requesting next open day of canteen %d from %s: %w", canteenID, from, ErrNoDataForDate)
+	}
+	return openDate, openMeals, nil
+}
+
+//requestMealsOfDates requests the meals of every date concurrently by at most Parallelism workers, the meals have the same order as the dates
+//a failing day does not stop the other days, the errors of all failed days are returned as *MealsError and their meals are nil
+func (c *Client) requestMealsOfDates(ctx context.Context, canteenID uint32, dates []CanteenDate) ([][]CanteenMeal, error) {
+	canteenMealList := make([][]CanteenMeal, len(dates))
+	dayErrors := make([]error, len(dates))
+
+	//the errors of the days are collected instead of returned, so a failing day does not cancel the other days
+	err := forEach(ctx, len(dates), c.parallelism(), func(ctx context.Context, index int) error {
+		//there are no meals on closed days, so they are not requested
+		if dates[index].Closed {
+			canteenMealList[index] = []CanteenMeal{}
+			return nil
+		}
+		canteenMealList[index], dayErrors[index] = c.requestCanteenMeals(ctx, canteenID, dates[index].Date)
 		return nil
+	})
+	//when the user gave up, the remaining days failed as well
+	if err != nil {
+		return canteenMealList, err
 	}
 
-	// Add a Path Segment (Path segment is automatically escaped)
-	baseURL.Path += "/canteens/" + strconv.Itoa(int(canteendID)) + "/days/" + canteenDate + "/meals"
+	mealsErr := &MealsError{}
+	for index, err := range dayErrors {
+		if err != nil {
+			mealsErr.Days = append(mealsErr.Days, DayError{Date: dates[index].Date, Err: err})
+		}
+	}
+	if len(mealsErr.Days) > 0 {
+		return canteenMealList, mealsErr
+	}
+	return canteenMealList, nil
+}
+
+//closedError returns a *ClosedError for the closed date of a canteen, the next open day is looked up in the following days
+//when the lookup fails, the error has no next open day
+func (c *Client) closedError(ctx context.Context, canteenID uint32, date string) error {
+	closedErr := &ClosedError{Date: date}
 
-	resp, err := http.Get(baseURL.String())
+	dates, err := c.requestDatesOfCanteen(ctx, canteenID, date, 0, daysPageLimit)
 	if err != nil {
-		log.Println("ERROR: Something went wrong when requesting a list of meals!", err.Error())
-		return nil
+		c.logf("looking up the next open day after %s failed: %v", date, err)
+		return closedErr
 	}
+	for _, nextDate := range dates {
+		if nextDate.Date > date && nextDate.Closed == false {
+			closedErr.NextOpenDate = nextDate.Date
+			break
+		}
+	}
+	return closedErr
+}
 
-	defer resp.Body.Close()
+//requestMealDays requests days of a canteen together with their meals from the /canteens/{id}/meals endpoint
+//startDate, page and limit work like for requestDatesOfCanteen, when they are empty or 0 they are not sent
+func (c *Client) requestMealDays(ctx context.Context, canteenID uint32, startDate string, page uint32, limit uint32) ([]mealDay, error) {
+	params := url.Values{}
+	if startDate != "" {
+		params.Add("start", startDate)
+	}
+	if page != 0 {
+		params.Add("page", strconv.Itoa(int(page)))
+	}
+	if limit != 0 {
+		params.Add("limit", strconv.Itoa(int(limit)))
+	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	body, _, err := c.get(ctx, "/canteens/"+strconv.Itoa(int(canteenID))+"/meals", params)
 	if err != nil {
-		log.Println("ERROR: Something went wrong when processing the result of requesting a list of all meals for a day!", err.Error())
-		return nil
+		return nil, fmt.Errorf("requesting meals of canteen %d: %w", canteenID, err)
+	}
+
+	var days []mealDay
+	err = decodeJSON(body, &days)
+	if err != nil {
+		return nil, fmt.Errorf("parsing meals of canteen %d: %w", canteenID, err)
+	}
+	return days, nil
+}
+
+//bulkUnsupported reports whether a failed request of the /canteens/{id}/meals endpoint is worth repeating with a request for every day
+//this is the case when the API does not implement the endpoint, f.e. a mirror answering 404 Not Found, 405 Method Not Allowed or 501 Not Implemented, or when its response can not be read
+//network errors, rate limits and other server errors would fail for every day as well and only multiply the requests
+func bulkUnsupported(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			return true
+		}
+		return false
+	}
+	return errors.Is(err, ErrInvalidResponse)
+}
+
+//splitMealDays splits days with meals into the dates and the meals of every date
+func splitMealDays(days []mealDay) ([]CanteenDate, [][]CanteenMeal) {
+	dates := make([]CanteenDate, len(days))
+	meals := make([][]CanteenMeal, len(days))
+	for i, day := range days {
+		dates[i] = day.CanteenDate
+		meals[i] = day.Meals
+		//closed days have no meals, but the API may send null
+		if meals[i] == nil {
+			meals[i] = []CanteenMeal{}
+		}
+	}
+	return dates, meals
+}
+
+//RequestCanteenMealOfToday returns the canteenMeal for the current day
+func (c *Client) RequestCanteenMealOfToday(canteenID uint32) (*CanteenDate, []CanteenMeal, error) {
+	return c.RequestCanteenMealOfTodayContext(context.Background(), canteenID)
+}
+
+//RequestCanteenMealOfTodayContext returns the canteenMeal for the current day, the requests are aborted when ctx is cancelled
+//this functions makes a requestCanteenDate request to see if the canteen is open and if there is any information provided about the meals
+//when the canteen is closed on this day, the date is returned together with a *ClosedError which knows the next open day
+func (c *Client) RequestCanteenMealOfTodayContext(ctx context.Context, canteenID uint32) (*CanteenDate, []CanteenMeal, error) {
+	canteenDateToday, err := c.RequestCanteenDateTodayContext(ctx, canteenID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if canteenDateToday.Closed {
+		return canteenDateToday, nil, c.closedError(ctx, canteenID, canteenDateToday.Date)
+	}
+
+	canteenMeals, err := c.requestCanteenMeals(ctx, canteenID, canteenDateToday.Date)
+	if err != nil {
+		return nil, nil, err
+	}
+	return canteenDateToday, canteenMeals, nil
+}
+
+//requestCanteenMeals is a function which requests a list of meals for a given canteen with a canteenID and a canteenDate
+func (c *Client) requestCanteenMeals(ctx context.Context, canteendID uint32, canteenDate string) ([]CanteenMeal, error) {
+	body, _, err := c.get(ctx, "/canteens/"+strconv.Itoa(int(canteendID))+"/days/"+canteenDate+"/meals", nil)
+	//the API answers with 404 when there are no meals for the date
+	if isNotFound(err) {
+		return nil, fmt.Errorf("requesting meals of canteen %d for %s: %w", canteendID, canteenDate, ErrNoDataForDate)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("requesting meals of canteen %d for %s: %w", canteendID, canteenDate, err)
 	}
 
 	var canteenMeals []CanteenMeal
-	err = json.Unmarshal(body, &canteenMeals)
+	err = decodeJSON(body, &canteenMeals)
 	if err != nil {
-		log.Println("ERROR: Something went wrong when trying to parse the requestCantenenMeal result!", err.Error())
-		return nil
+		return nil, fmt.Errorf("parsing meals of canteen %d for %s: %w", canteendID, canteenDate, err)
 	}
 
-	return canteenMeals
+	return canteenMeals, nil
 }
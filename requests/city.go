@@ -0,0 +1,58 @@
+package requests
+
+import (
+	"sort"
+	"strings"
+)
+
+//CityCount is a city together with the number of canteens in it
+type CityCount struct {
+	City  string `json:"city"`
+	Count int    `json:"count"`
+}
+
+//Cities returns the distinct cities of the canteens with the number of canteens in each city sorted by the name of the city
+//cities which only differ in case or umlauts are counted as one city, canteens without a city are skipped
+func Cities(canteens []Canteen) []CityCount {
+	indexOf := map[string]int{}
+	cities := []CityCount{}
+	for _, canteen := range canteens {
+		key := cityKey(canteen.City)
+		if key == "" {
+			continue
+		}
+		if index, ok := indexOf[key]; ok {
+			cities[index].Count++
+			continue
+		}
+		indexOf[key] = len(cities)
+		cities = append(cities, CityCount{City: strings.TrimSpace(canteen.City), Count: 1})
+	}
+
+	sort.SliceStable(cities, func(i, j int) bool {
+		return cityKey(cities[i].City) < cityKey(cities[j].City)
+	})
+	return cities
+}
+
+//CanteensInCity returns the canteens in the given city, the comparison ignores the case and umlauts, so "muenchen" finds the canteens in "München"
+func CanteensInCity(canteens []Canteen, city string) []Canteen {
+	key, plainKey := cityKey(city), cityKey(plainReplacer.Replace(strings.ToLower(city)))
+	cityCanteens := []Canteen{}
+	if key == "" {
+		return cityCanteens
+	}
+
+	for _, canteen := range canteens {
+		canteenKey := cityKey(canteen.City)
+		if canteenKey == key || canteenKey == plainKey || cityKey(plainReplacer.Replace(strings.ToLower(canteen.City))) == plainKey {
+			cityCanteens = append(cityCanteens, canteen)
+		}
+	}
+	return cityCanteens
+}
+
+//cityKey returns the folded name of a city which is used for comparing cities, f.e. "Frankfurt (Oder)" -> "frankfurt oder"
+func cityKey(city string) string {
+	return strings.Join(searchWords(city), " ")
+}
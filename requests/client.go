@@ -0,0 +1,233 @@
+package requests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	openMensaEndpoint = "https://openmensa.org/api/v2"
+
+	//DefaultTimeout is the timeout of a single request made by a client created with NewClient
+	DefaultTimeout = 30 * time.Second
+	//DefaultUserAgent is the User-Agent header sent by a client created with NewClient
+	DefaultUserAgent = "gomensa"
+	//DefaultParallelism is the number of concurrent requests a client makes when requesting multiple pages or days
+	DefaultParallelism = 5
+)
+
+//Client represents a connection to an OpenMensa API, it carries the base URL of the API and the http.Client which is used for all requests
+type Client struct {
+	//BaseURL is the URL of the OpenMensa API, f.e. https://openmensa.org/api/v2
+	BaseURL string
+	//HTTPClient is used for making the requests, when nil http.DefaultClient is used
+	HTTPClient *http.Client
+	//Timeout limits the duration of a single request, a value of 0 means no timeout
+	Timeout time.Duration
+	//UserAgent is sent as the User-Agent header with every request, when empty the header of the HTTPClient is used
+	UserAgent string
+	//Parallelism is the maximal number of concurrent requests when requesting multiple pages or the meals of multiple days, a value below 1 means DefaultParallelism
+	Parallelism int
+	//Cache stores the responses on disk, when nil every call makes a request
+	Cache *Cache
+	//Retry decides how often a request is repeated after a network error, 429 Too Many Requests or a temporary server error
+	Retry RetryPolicy
+	//RateLimiter limits the number of requests per second, it is shared by all requests of the client, when nil there is no limit
+	RateLimiter *RateLimiter
+	//Logger receives verbose information about the requests, f.e. retries, when nil nothing is logged
+	Logger *log.Logger
+}
+
+//DefaultClient is the client used by all package-level Request functions
+var DefaultClient = NewClient("")
+
+//NewClient returns a client for the OpenMensa API located at baseURL, when baseURL is empty the official openmensa.org endpoint is used
+func NewClient(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = openMensaEndpoint
+	}
+
+	return &Client{
+		BaseURL:     strings.TrimSuffix(baseURL, "/"),
+		HTTPClient:  &http.Client{},
+		Timeout:     DefaultTimeout,
+		UserAgent:   DefaultUserAgent,
+		Parallelism: DefaultParallelism,
+		Retry:       DefaultRetryPolicy,
+	}
+}
+
+//httpClient returns the http.Client of c or the http.DefaultClient when none is set
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+//parallelism returns the number of concurrent requests the client is allowed to make
+func (c *Client) parallelism() int {
+	if c.Parallelism < 1 {
+		return DefaultParallelism
+	}
+	return c.Parallelism
+}
+
+//get makes a GET request for the given path relative to the base URL of the client, it returns the body and the header of the response
+//when the client has a cache, fresh cached responses are returned without making a request
+//the request is aborted when ctx is cancelled or the timeout of the client is exceeded
+func (c *Client) get(ctx context.Context, path string, params url.Values) ([]byte, http.Header, error) {
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed URL: %w", err)
+	}
+
+	// Add a Path Segment (Path segment is automatically escaped)
+	baseURL.Path = strings.TrimSuffix(baseURL.Path, "/") + path
+
+	// Add Query Parameters to the URL
+	if params != nil {
+		baseURL.RawQuery = params.Encode()
+	}
+
+	if c.Cache == nil {
+		return c.fetch(ctx, baseURL, nil)
+	}
+
+	var entry *cacheEntry
+	if c.Cache.Refresh == false {
+		var fresh bool
+		entry, fresh = c.Cache.load(baseURL)
+		//when offline, an expired response is still better than nothing
+		if entry != nil && (fresh || c.Cache.Offline) {
+			c.logf("using cached response for %s", baseURL)
+			return []byte(entry.Body), entry.header(), nil
+		}
+	}
+
+	if c.Cache.Offline {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotCached, baseURL.String())
+	}
+
+	//an expired entry is revalidated with its ETag or Last-Modified header instead of downloading it again
+	body, header, err := c.fetch(ctx, baseURL, entry.validators())
+	if errors.Is(err, errNotModified) {
+		c.logf("cached response for %s is still valid", baseURL)
+		//storing the entry again restarts its TTL
+		entry.revalidated(header)
+		c.Cache.store(baseURL, []byte(entry.Body), entry.header())
+		return []byte(entry.Body), entry.header(), nil
+	}
+	if err != nil {
+		//an expired response is better than nothing when the connection or the server fails, f.e. on a train
+		if entry != nil && retryable(ctx, err) {
+			c.logf("revalidating the cached response for %s failed: %v, using the expired response", baseURL, err)
+			return []byte(entry.Body), entry.header(), nil
+		}
+		return nil, nil, err
+	}
+
+	//the cache is only an optimisation, so a failing write does not fail the request
+	c.Cache.store(baseURL, body, header)
+
+	return body, header, nil
+}
+
+//logf writes a verbose message to the logger of the client
+func (c *Client) logf(format string, v ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, v...)
+	}
+}
+
+//errNotModified is returned by fetch together with the response header when a conditional request was answered with 304 Not Modified
+var errNotModified = errors.New("not modified")
+
+//fetch makes a GET request for the given URL and returns the body and the header of a successful response
+//the conditional headers, f.e. If-None-Match, are added to the request
+//failed requests are repeated according to the retry policy of the client
+func (c *Client) fetch(ctx context.Context, requestURL *url.URL, conditional http.Header) ([]byte, http.Header, error) {
+	for attempt := 1; ; attempt++ {
+		body, header, err := c.fetchOnce(ctx, requestURL, conditional)
+		if err == nil || attempt >= c.Retry.MaxAttempts || retryable(ctx, err) == false {
+			return body, header, err
+		}
+
+		var retryAfter time.Duration
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			retryAfter = apiErr.RetryAfter
+		}
+
+		delay := c.Retry.delay(attempt, retryAfter)
+		c.logf("attempt %d of %d for %s failed: %v, retrying in %s", attempt, c.Retry.MaxAttempts, requestURL, err, delay)
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+//fetchOnce makes a single GET request for the given URL, see fetch
+func (c *Client) fetchOnce(ctx context.Context, requestURL *url.URL, conditional http.Header) ([]byte, http.Header, error) {
+	//waiting for the rate limiter does not count towards the timeout of the request
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	c.logf("requesting %s", requestURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	for key, values := range conditional {
+		req.Header[key] = values
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && len(conditional) > 0 {
+		return nil, resp.Header, errNotModified
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, newAPIError(requestURL.String(), resp.StatusCode, resp.Header, body)
+	}
+
+	//f.e. the login page of a captive portal or a misconfigured mirror
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && strings.Contains(contentType, "json") == false {
+		return nil, nil, fmt.Errorf("%w: unexpected content type %q for %s", ErrInvalidResponse, contentType, requestURL.String())
+	}
+
+	return body, resp.Header, nil
+}
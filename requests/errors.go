@@ -0,0 +1,168 @@
+package requests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	//ErrCanteenNotFound is returned when the OpenMensa API does not know a canteen with the requested ID
+	ErrCanteenNotFound = errors.New("canteen not found")
+	//ErrNoDataForDate is returned when the OpenMensa API has no information about the requested date of a canteen
+	ErrNoDataForDate = errors.New("no data for this date")
+	//ErrRateLimited is returned when the OpenMensa API rejected a request because too many requests were made
+	ErrRateLimited = errors.New("rate limited by the OpenMensa API")
+	//ErrCanteenClosed is returned when the meals of a day are requested on which the canteen is closed, the error is a *ClosedError
+	ErrCanteenClosed = errors.New("canteen is closed")
+	//ErrInvalidDate is returned when a date does not follow the format YYYY-MM-DD
+	ErrInvalidDate = errors.New("invalid date, expected format YYYY-MM-DD")
+	//ErrInvalidDateRange is returned when the end of a range of dates is before its start
+	ErrInvalidDateRange = errors.New("invalid date range, the end is before the start")
+	//ErrServer is returned when the OpenMensa API answered with a 5xx status code
+	ErrServer = errors.New("OpenMensa API server error")
+	//ErrInvalidResponse is returned when the OpenMensa API answered with something that is not the expected JSON
+	ErrInvalidResponse = errors.New("invalid response from the OpenMensa API")
+)
+
+//APIError is returned when the OpenMensa API answered a request with an unsuccessful HTTP status code
+type APIError struct {
+	//URL is the requested URL
+	URL string
+	//StatusCode is the HTTP status code of the response
+	StatusCode int
+	//Body is the body of the response
+	Body string
+	//Message is the error message of the OpenMensa error payload, it is empty when the body did not contain one
+	Message string
+	//RetryAfter is the duration the server asked to wait before the next request, it is 0 when the response had no Retry-After header
+	RetryAfter time.Duration
+}
+
+//apiErrorPayload is the JSON body the OpenMensa API sends together with an unsuccessful status code
+type apiErrorPayload struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+//newAPIError creates an APIError for the given response and decodes the OpenMensa error payload of the body
+func newAPIError(url string, statusCode int, header http.Header, body []byte) *APIError {
+	apiErr := &APIError{
+		URL:        url,
+		StatusCode: statusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(header.Get("Retry-After"), time.Now()),
+	}
+
+	var payload apiErrorPayload
+	if json.Unmarshal(body, &payload) == nil {
+		apiErr.Message = payload.Message
+		if apiErr.Message == "" {
+			apiErr.Message = payload.Error
+		}
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("OpenMensa API returned status %d %s for %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.URL, e.Message)
+	}
+	return fmt.Sprintf("OpenMensa API returned status %d %s for %s", e.StatusCode, http.StatusText(e.StatusCode), e.URL)
+}
+
+//Unwrap makes errors.Is(err, ErrRateLimited) work for responses with the status 429 Too Many Requests and errors.Is(err, ErrServer) for all 5xx responses
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrServer
+	}
+	return nil
+}
+
+//ClosedError is returned when the meals of a day are requested on which the canteen is closed
+type ClosedError struct {
+	//Date is the requested date in the format YYYY-MM-DD
+	Date string
+	//NextOpenDate is the first day after Date on which the canteen is open, it is empty when the API does not know such a day
+	NextOpenDate string
+}
+
+func (e *ClosedError) Error() string {
+	if e.NextOpenDate == "" {
+		return fmt.Sprintf("canteen is closed on %s", e.Date)
+	}
+	return fmt.Sprintf("canteen is closed on %s, next open day is %s", e.Date, e.NextOpenDate)
+}
+
+//Unwrap makes errors.Is(err, ErrCanteenClosed) work
+func (e *ClosedError) Unwrap() error {
+	return ErrCanteenClosed
+}
+
+//DayError is the error of requesting the meals of a single day
+type DayError struct {
+	//Date is the date of the day in the format YYYY-MM-DD
+	Date string
+	//Err is the reason why the meals of the day could not be requested
+	Err error
+}
+
+func (e DayError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Date, e.Err)
+}
+
+func (e DayError) Unwrap() error {
+	return e.Err
+}
+
+//MealsError is returned when the meals of some days could not be requested, the meals of all other days are still returned
+type MealsError struct {
+	//Days are the failed days in the order of their dates
+	Days []DayError
+}
+
+func (e *MealsError) Error() string {
+	messages := make([]string, len(e.Days))
+	for i, day := range e.Days {
+		messages[i] = day.Error()
+	}
+	return fmt.Sprintf("could not request the meals of %d days: %s", len(e.Days), strings.Join(messages, "; "))
+}
+
+//Unwrap returns the error of the first failed day, so errors.Is(err, ErrServer) works like for a single request
+func (e *MealsError) Unwrap() error {
+	if len(e.Days) == 0 {
+		return nil
+	}
+	return e.Days[0].Err
+}
+
+//ErrorOf returns the error of the given date in the format YYYY-MM-DD or nil when its meals were requested successfully
+func (e *MealsError) ErrorOf(date string) error {
+	for _, day := range e.Days {
+		if day.Date == date {
+			return day.Err
+		}
+	}
+	return nil
+}
+
+//isNotFound reports whether err is caused by a 404 Not Found response
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+//decodeJSON parses the body of a response into value, a body which is not valid JSON results in ErrInvalidResponse
+func decodeJSON(body []byte, value interface{}) error {
+	if err := json.Unmarshal(body, value); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package requests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	//earthRadius is the mean radius of the earth in kilometers
+	earthRadius = 6371.0
+)
+
+//ErrInvalidCoordinates is returned when coordinates do not follow the format LAT,LON or are out of range
+var ErrInvalidCoordinates = errors.New("invalid coordinates, expected format LAT,LON")
+
+//ErrNearWithoutCoordinates is returned when canteens near a location are requested, but only canteens without coordinates should be listed
+var ErrNearWithoutCoordinates = errors.New("mensas without coordinates can not be near a location")
+
+//Coordinates is a geographical location, the OpenMensa API represents it as an array [latitude, longitude]
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+//CanteenDistance is a canteen together with its distance in kilometers to a location
+type CanteenDistance struct {
+	Canteen  Canteen `json:"canteen"`
+	Distance float64 `json:"distance"`
+}
+
+//UnmarshalJSON parses the [latitude, longitude] array of the OpenMensa API
+func (c *Coordinates) UnmarshalJSON(data []byte) error {
+	var latLng []float64
+	if err := json.Unmarshal(data, &latLng); err != nil {
+		return err
+	}
+	if len(latLng) != 2 {
+		return fmt.Errorf("expected 2 coordinates, got %d", len(latLng))
+	}
+	c.Latitude, c.Longitude = latLng[0], latLng[1]
+	return nil
+}
+
+//MarshalJSON returns the coordinates as [latitude, longitude] array like the OpenMensa API
+func (c Coordinates) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]float64{c.Latitude, c.Longitude})
+}
+
+func (c Coordinates) String() string {
+	return fmt.Sprintf("%.6f,%.6f", c.Latitude, c.Longitude)
+}
+
+//ParseCoordinates parses a location in the format LAT,LON, f.e. 51.3397,12.3731
+func ParseCoordinates(location string) (Coordinates, error) {
+	parts := strings.Split(location, ",")
+	if len(parts) != 2 {
+		return Coordinates{}, fmt.Errorf("%q: %w", location, ErrInvalidCoordinates)
+	}
+
+	latitude, errLat := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	longitude, errLon := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if errLat != nil || errLon != nil || math.Abs(latitude) > 90 || math.Abs(longitude) > 180 {
+		return Coordinates{}, fmt.Errorf("%q: %w", location, ErrInvalidCoordinates)
+	}
+	return Coordinates{Latitude: latitude, Longitude: longitude}, nil
+}
+
+//Distance returns the great-circle distance between a and b in kilometers using the haversine formula
+func Distance(a Coordinates, b Coordinates) float64 {
+	toRadians := func(degree float64) float64 { return degree * math.Pi / 180 }
+
+	deltaLat := toRadians(b.Latitude - a.Latitude)
+	deltaLon := toRadians(b.Longitude - a.Longitude)
+
+	h := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
+		math.Cos(toRadians(a.Latitude))*math.Cos(toRadians(b.Latitude))*math.Sin(deltaLon/2)*math.Sin(deltaLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+//CanteensNear returns the canteens within radius kilometers of the location sorted by their distance, the nearest first
+//canteens without coordinates are skipped, a radius of 0 or below includes all canteens
+func CanteensNear(canteens []Canteen, location Coordinates, radius float64) []CanteenDistance {
+	nearCanteens := []CanteenDistance{}
+	for _, canteen := range canteens {
+		if canteen.Coordinates == nil {
+			continue
+		}
+		distance := Distance(location, *canteen.Coordinates)
+		if radius > 0 && distance > radius {
+			continue
+		}
+		nearCanteens = append(nearCanteens, CanteenDistance{Canteen: canteen, Distance: distance})
+	}
+
+	sort.SliceStable(nearCanteens, func(i, j int) bool {
+		return nearCanteens[i].Distance < nearCanteens[j].Distance
+	})
+	return nearCanteens
+}
@@ -0,0 +1,172 @@
+package requests
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"strings"
+)
+
+//formatHTML prints meal plans and canteens as HTML tables, f.e. for dashboards
+const formatHTML = "html"
+
+//htmlTemplates are the HTML fragments of the html renderer, html/template escapes all names, notes and errors
+var htmlTemplates = template.Must(template.New("html").Funcs(template.FuncMap{
+	"price":         priceCell,
+	"priceHeader":   func(group PriceGroup) string { return priceHeaders[group] },
+	"weekday":       weekdayOf,
+	"closedMessage": closedMessage,
+	"status":        openingStatus,
+	"add":           func(a int, b int) int { return a + b },
+	"join": func(notes []string) string {
+		return strings.Join(notes, ", ")
+	},
+}).Parse(`
+{{define "canteen"}}<section class="canteen">
+<h1>{{.Name}}</h1>
+<dl>
+<dt>ID</dt><dd>{{.ID}}</dd>
+<dt>City</dt><dd>{{.City}}</dd>
+<dt>Address</dt><dd>{{.Address}}</dd>
+</dl>
+</section>
+{{end}}
+
+{{define "canteens"}}<table class="canteens">
+<thead><tr>{{if .Distances}}<th class="number">Distance</th>{{end}}{{if .Matches}}<th class="number">#</th>{{end}}<th class="number">ID</th><th>Name</th><th>City</th><th>Address</th></tr></thead>
+<tbody>
+{{range .Canteens}}<tr><td class="number">{{.ID}}</td><td>{{.Name}}</td><td>{{.City}}</td><td>{{.Address}}</td></tr>
+{{end}}{{range .Distances}}<tr><td class="number">{{printf "%.2f" .Distance}} km</td><td class="number">{{.Canteen.ID}}</td><td>{{.Canteen.Name}}</td><td>{{.Canteen.City}}</td><td>{{.Canteen.Address}}</td></tr>
+{{end}}{{range $i, $match := .Matches}}<tr><td class="number">{{add $i 1}}</td><td class="number">{{.Canteen.ID}}</td><td>{{.Canteen.Name}}</td><td>{{.Canteen.City}}</td><td>{{.Canteen.Address}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+
+{{define "cities"}}<table class="cities">
+<thead><tr><th>City</th><th class="number">Mensas</th></tr></thead>
+<tbody>
+{{range .}}<tr><td>{{.City}}</td><td class="number">{{.Count}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+
+{{define "meals"}}<section class="meals">
+<h1>{{.Canteen.Name}}</h1>
+{{$priceGroups := .PriceGroups}}{{range .Days}}<h2>{{weekday .Date}}</h2>
+{{if .Closed}}<p class="closed">{{closedMessage .}}</p>
+{{else if .Error}}<p class="error">Could not retrieve the meals of this day: {{.Error}}</p>
+{{else if not .Meals}}<p class="empty">No meals</p>
+{{else}}<table class="day">
+<thead><tr><th class="number">#</th><th>Category</th><th>Meal</th><th>Notes</th>{{range $priceGroups}}<th class="number">{{priceHeader .}}</th>{{end}}</tr></thead>
+<tbody>
+{{range $i, $meal := .Meals}}<tr><td class="number">{{add $i 1}}</td><td>{{.Category}}</td><td>{{.Name}}</td><td>{{join .Notes}}</td>{{range $priceGroups}}<td class="number">{{price $meal.Prices .}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+{{end}}{{end}}</section>
+{{end}}
+
+{{define "dates"}}<section class="dates">
+<h1>{{.Canteen.Name}}</h1>
+<table>
+<thead><tr><th>Date</th><th>Status</th></tr></thead>
+<tbody>
+{{range .Dates}}<tr class="{{status .Closed}}"><td>{{weekday .Date}}</td><td>{{status .Closed}}</td></tr>
+{{end}}</tbody>
+</table>
+</section>
+{{end}}
+
+{{define "page"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 60em; padding: 0 1em; color: #222; }
+h2 { margin-top: 1.5em; border-bottom: 1px solid #ddd; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 0.4em 0.6em; border-bottom: 1px solid #eee; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+.number { text-align: right; white-space: nowrap; }
+.closed { color: #b00020; }
+.error { color: #b00020; font-style: italic; }
+.empty { color: #666; font-style: italic; }
+dt { font-weight: bold; }
+</style>
+</head>
+<body>
+{{.Content}}</body>
+</html>
+{{end}}`))
+
+//htmlRenderer writes meal plans and canteens as HTML, either as fragment to embed into a page or as standalone page with embedded CSS
+type htmlRenderer struct {
+	options RenderOptions
+}
+
+func newHTMLRenderer(options RenderOptions) Renderer {
+	return htmlRenderer{options: options}
+}
+
+//RenderCanteen writes the name of the canteen as heading followed by its ID, city and address
+func (r htmlRenderer) RenderCanteen(w io.Writer, canteen Canteen) error {
+	return r.execute(w, canteen.Name, "canteen", canteen)
+}
+
+//RenderCanteens writes a table with the ID, name, city and address of the canteens
+func (r htmlRenderer) RenderCanteens(w io.Writer, canteens []Canteen) error {
+	return r.execute(w, "Mensas", "canteens", TemplateData{Canteens: canteens})
+}
+
+//RenderCanteenDistances writes a table of canteens with their distance in the first column
+func (r htmlRenderer) RenderCanteenDistances(w io.Writer, canteens []CanteenDistance) error {
+	return r.execute(w, "Mensas", "canteens", TemplateData{Distances: canteens})
+}
+
+//RenderCanteenMatches writes a table of the search results, the best match first
+func (r htmlRenderer) RenderCanteenMatches(w io.Writer, matches []CanteenMatch) error {
+	return r.execute(w, "Mensas", "canteens", TemplateData{Matches: matches})
+}
+
+//RenderCities writes a table of the cities with their number of canteens
+func (r htmlRenderer) RenderCities(w io.Writer, cities []CityCount) error {
+	return r.execute(w, "Cities", "cities", cities)
+}
+
+//RenderMeals writes the name of the canteen as heading followed by the day with a table of its meals
+func (r htmlRenderer) RenderMeals(w io.Writer, canteen Canteen, day MealsOfDay) error {
+	return r.RenderWeek(w, canteen, []MealsOfDay{day})
+}
+
+//RenderWeek writes the name of the canteen as heading followed by every day with a table of its meals
+func (r htmlRenderer) RenderWeek(w io.Writer, canteen Canteen, days []MealsOfDay) error {
+	data := struct {
+		Canteen     Canteen
+		Days        []MealsOfDay
+		PriceGroups []PriceGroup
+	}{canteen, days, shownPriceGroups(r.options.PriceGroup)}
+	return r.execute(w, canteen.Name, "meals", data)
+}
+
+//RenderDates writes the name of the canteen as heading followed by a table of the days with their opening status
+func (r htmlRenderer) RenderDates(w io.Writer, canteen Canteen, dates []CanteenDate) error {
+	return r.execute(w, canteen.Name, "dates", TemplateData{Canteen: canteen, Dates: dates})
+}
+
+//execute writes the fragment with the given name, with the Standalone option the fragment is the content of a page with the title
+func (r htmlRenderer) execute(w io.Writer, title string, name string, data interface{}) error {
+	if r.options.Standalone == false {
+		return htmlTemplates.ExecuteTemplate(w, name, data)
+	}
+
+	var content bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&content, name, data); err != nil {
+		return err
+	}
+	//the content was escaped by html/template when the fragment was executed
+	return htmlTemplates.ExecuteTemplate(w, "page", struct {
+		Title   string
+		Content template.HTML
+	}{title, template.HTML(content.String())})
+}
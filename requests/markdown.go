@@ -0,0 +1,196 @@
+package requests
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//formatMarkdown prints meal plans and canteens as markdown tables, f.e. for wikis
+const formatMarkdown = "markdown"
+
+//markdownReplacer escapes the characters which have a meaning in markdown, so the names of meals are printed as they are
+var markdownReplacer = strings.NewReplacer(
+	"\\", "\\\\", "|", "\\|", "*", "\\*", "_", "\\_", "`", "\\`",
+	"[", "\\[", "]", "\\]", "<", "&lt;", ">", "&gt;",
+	"\r\n", " ", "\n", " ",
+)
+
+//markdownRenderer writes meal plans and canteens as markdown with a table for the meals of every day
+type markdownRenderer struct {
+	options RenderOptions
+}
+
+func newMarkdownRenderer(options RenderOptions) Renderer {
+	return markdownRenderer{options: options}
+}
+
+//RenderCanteen writes the name of the canteen as heading followed by its ID, city and address
+func (r markdownRenderer) RenderCanteen(w io.Writer, canteen Canteen) error {
+	_, err := fmt.Fprintf(w, "# %s\n\n- **ID:** %d\n- **City:** %s\n- **Address:** %s\n",
+		escapeMarkdown(canteen.Name), canteen.ID, escapeMarkdown(canteen.City), escapeMarkdown(canteen.Address))
+	return err
+}
+
+//RenderCanteens writes a table with the ID, name, city and address of the canteens
+func (r markdownRenderer) RenderCanteens(w io.Writer, canteens []Canteen) error {
+	rows := make([][]string, len(canteens))
+	for i, canteen := range canteens {
+		rows[i] = []string{strconv.Itoa(canteen.ID), canteen.Name, canteen.City, canteen.Address}
+	}
+	return writeMarkdownTable(w, []string{"ID", "Name", "City", "Address"}, []bool{true, false, false, false}, rows)
+}
+
+//RenderCanteenDistances writes a table of canteens with their distance in the first column
+func (r markdownRenderer) RenderCanteenDistances(w io.Writer, canteens []CanteenDistance) error {
+	rows := make([][]string, len(canteens))
+	for i, canteen := range canteens {
+		rows[i] = []string{fmt.Sprintf("%.2f km", canteen.Distance), strconv.Itoa(canteen.Canteen.ID), canteen.Canteen.Name, canteen.Canteen.City, canteen.Canteen.Address}
+	}
+	return writeMarkdownTable(w, []string{"Distance", "ID", "Name", "City", "Address"}, []bool{true, true, false, false, false}, rows)
+}
+
+//RenderCanteenMatches writes a table of the search results, the best match first
+func (r markdownRenderer) RenderCanteenMatches(w io.Writer, matches []CanteenMatch) error {
+	if len(matches) == 0 {
+		return writeText(w, "No mensa matches your search.")
+	}
+
+	rows := make([][]string, len(matches))
+	for i, match := range matches {
+		rows[i] = []string{strconv.Itoa(i + 1), strconv.Itoa(match.Canteen.ID), match.Canteen.Name, match.Canteen.City, match.Canteen.Address}
+	}
+	return writeMarkdownTable(w, []string{"#", "ID", "Name", "City", "Address"}, []bool{true, true, false, false, false}, rows)
+}
+
+//RenderCities writes a table of the cities with their number of canteens
+func (r markdownRenderer) RenderCities(w io.Writer, cities []CityCount) error {
+	rows := make([][]string, len(cities))
+	for i, city := range cities {
+		rows[i] = []string{city.City, strconv.Itoa(city.Count)}
+	}
+	return writeMarkdownTable(w, []string{"City", "Mensas"}, []bool{false, true}, rows)
+}
+
+//RenderMeals writes the name of the canteen as heading followed by the day with its meals
+func (r markdownRenderer) RenderMeals(w io.Writer, canteen Canteen, day MealsOfDay) error {
+	return r.RenderWeek(w, canteen, []MealsOfDay{day})
+}
+
+//RenderWeek writes the name of the canteen as heading followed by every day with a table of its meals
+func (r markdownRenderer) RenderWeek(w io.Writer, canteen Canteen, days []MealsOfDay) error {
+	if _, err := fmt.Fprintf(w, "# %s\n", escapeMarkdown(canteen.Name)); err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		if _, err := fmt.Fprintf(w, "\n## %s\n\n", weekdayOf(day.Date)); err != nil {
+			return err
+		}
+
+		var err error
+		switch {
+		case day.Closed:
+			_, err = fmt.Fprintf(w, "*%s*\n", closedMessage(day))
+		case day.Error != "":
+			_, err = fmt.Fprintf(w, "*Could not retrieve the meals of this day: %s*\n", escapeMarkdown(day.Error))
+		case len(day.Meals) == 0:
+			_, err = fmt.Fprintln(w, "*No meals*")
+		default:
+			header, rightAligned, rows := mealColumns(day.Meals, r.options.PriceGroup)
+			err = writeMarkdownTable(w, header, rightAligned, rows)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//RenderDates writes the name of the canteen as heading followed by a table of the days with their opening status
+func (r markdownRenderer) RenderDates(w io.Writer, canteen Canteen, dates []CanteenDate) error {
+	if _, err := fmt.Fprintf(w, "# %s\n\n", escapeMarkdown(canteen.Name)); err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(dates))
+	for i, date := range dates {
+		rows[i] = []string{weekdayOf(date.Date), openingStatus(date.Closed)}
+	}
+	return writeMarkdownTable(w, []string{"Date", "Status"}, []bool{false, false}, rows)
+}
+
+//mealColumns returns the header and the rows of a table of meals with their category, notes and prices
+//when a price group is set only its price is contained, otherwise the prices for students, employees and others
+func mealColumns(meals []CanteenMeal, group PriceGroup) ([]string, []bool, [][]string) {
+	header := []string{"#", "Category", "Meal", "Notes"}
+	rightAligned := []bool{true, false, false, false}
+
+	priceGroups := shownPriceGroups(group)
+	for _, priceGroup := range priceGroups {
+		header = append(header, priceHeaders[priceGroup])
+		rightAligned = append(rightAligned, true)
+	}
+
+	rows := make([][]string, len(meals))
+	for i, meal := range meals {
+		rows[i] = []string{strconv.Itoa(i + 1), meal.Category, meal.Name, strings.Join(meal.Notes, ", ")}
+		for _, priceGroup := range priceGroups {
+			rows[i] = append(rows[i], priceCell(meal.Prices, priceGroup))
+		}
+	}
+	return header, rightAligned, rows
+}
+
+//closedMessage returns that the canteen is closed on the day and its next open day with the weekday and date when it is known
+//other than ClosedToString it does not say today or tomorrow, because a published meal plan is read later
+func closedMessage(day MealsOfDay) string {
+	if day.NextOpenDate == "" {
+		return "closed"
+	}
+	return "closed, the next open day is " + weekdayOf(day.NextOpenDate)
+}
+
+//openingStatus returns closed or open
+func openingStatus(closed bool) string {
+	if closed {
+		return "closed"
+	}
+	return "open"
+}
+
+//escapeMarkdown escapes text so it is printed as it is in markdown and does not break a table
+func escapeMarkdown(text string) string {
+	return markdownReplacer.Replace(text)
+}
+
+//writeMarkdownTable writes a markdown table, the cells are escaped and rightAligned columns are aligned to the right
+func writeMarkdownTable(w io.Writer, header []string, rightAligned []bool, rows [][]string) error {
+	builder := strings.Builder{}
+
+	separator := make([]string, len(header))
+	for i := range header {
+		separator[i] = "---"
+		if rightAligned[i] {
+			separator[i] = "--:"
+		}
+	}
+
+	writeMarkdownRow(&builder, header)
+	builder.WriteString("| " + strings.Join(separator, " | ") + " |\n")
+	for _, row := range rows {
+		writeMarkdownRow(&builder, row)
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+func writeMarkdownRow(builder *strings.Builder, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = escapeMarkdown(cell)
+	}
+	builder.WriteString("| " + strings.Join(escaped, " | ") + " |\n")
+}
@@ -0,0 +1,247 @@
+package requests
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	//formatText is the human readable output of the prettyfier
+	formatText = "text"
+	//formatJSON writes a single JSON document, lists are written as JSON array
+	formatJSON = "json"
+	//formatJSONL writes every element of a list as JSON object on its own line
+	formatJSONL = "jsonl"
+	//formatCSV writes a header line and one line for every element of a list
+	formatCSV = "csv"
+)
+
+//MealsOfDay are the meals of a canteen on a single day, it is what the renderers get for the meal commands
+type MealsOfDay struct {
+	Date   string `json:"date"`
+	Closed bool   `json:"closed"`
+	//NextOpenDate is the next day on which the canteen is open when it is closed on Date and the next open day is known
+	NextOpenDate string        `json:"nextOpenDate,omitempty"`
+	Meals        []CanteenMeal `json:"meals"`
+	//Error is the reason why the meals of the day could not be requested
+	Error string `json:"error,omitempty"`
+}
+
+//NewMealsOfDay combines the date and meals returned by a single day meal request into a MealsOfDay for the renderers
+//a *ClosedError adds the next open day to the record
+func NewMealsOfDay(date CanteenDate, meals []CanteenMeal, err error) MealsOfDay {
+	day := NewMealsOfDays([]CanteenDate{date}, [][]CanteenMeal{meals}, nil)[0]
+
+	var closedErr *ClosedError
+	if errors.As(err, &closedErr) {
+		day.NextOpenDate = closedErr.NextOpenDate
+	}
+	return day
+}
+
+//NewMealsOfDays combines the dates and meals returned by the meal requests into MealsOfDays for the renderers
+//the errors of the failed days of a *MealsError are added to their days
+func NewMealsOfDays(dates []CanteenDate, meals [][]CanteenMeal, err error) []MealsOfDay {
+	var mealsErr *MealsError
+	errors.As(err, &mealsErr)
+
+	days := make([]MealsOfDay, len(dates))
+	for i, date := range dates {
+		days[i] = MealsOfDay{Date: date.Date, Closed: date.Closed, Meals: []CanteenMeal{}}
+		if i < len(meals) && meals[i] != nil {
+			days[i].Meals = meals[i]
+		}
+		if mealsErr != nil {
+			if dayErr := mealsErr.ErrorOf(date.Date); dayErr != nil {
+				days[i].Error = dayErr.Error()
+			}
+		}
+	}
+	return days
+}
+
+//mealsRecord is the record of the structured output formats for the meals of a day, the canteen tells the output of several canteens apart
+type mealsRecord struct {
+	Canteen Canteen `json:"canteen"`
+	MealsOfDay
+}
+
+//dateRecord is the record of the structured output formats for the opening status of a day
+type dateRecord struct {
+	Canteen Canteen `json:"canteen"`
+	CanteenDate
+}
+
+//structuredRenderer writes everything in the machine readable format json, jsonl or csv
+type structuredRenderer struct {
+	format string
+}
+
+//newStructuredRenderer returns the factory of the renderer of a structured format, the structured formats ignore the RenderOptions
+func newStructuredRenderer(format string) RendererFactory {
+	return func(options RenderOptions) Renderer {
+		return structuredRenderer{format: format}
+	}
+}
+
+//RenderCanteen writes a single canteen, in the JSON format as object instead of an array
+func (r structuredRenderer) RenderCanteen(w io.Writer, canteen Canteen) error {
+	return r.renderCanteens(w, []Canteen{canteen}, true)
+}
+
+//RenderCanteens writes a list of canteens
+func (r structuredRenderer) RenderCanteens(w io.Writer, canteens []Canteen) error {
+	return r.renderCanteens(w, canteens, false)
+}
+
+//renderCanteens writes canteens, single writes the first canteen as JSON object instead of an array
+func (r structuredRenderer) renderCanteens(w io.Writer, canteens []Canteen, single bool) error {
+	records := make([]interface{}, len(canteens))
+	rows := make([][]string, len(canteens))
+	for i, canteen := range canteens {
+		records[i] = canteen
+		rows[i] = canteenRow(canteen)
+	}
+	return writeStructured(w, r.format, records, canteenHeader, rows, single)
+}
+
+//RenderCanteenDistances writes a list of canteens with their distance
+func (r structuredRenderer) RenderCanteenDistances(w io.Writer, canteens []CanteenDistance) error {
+	records := make([]interface{}, len(canteens))
+	rows := make([][]string, len(canteens))
+	for i, canteen := range canteens {
+		records[i] = canteen
+		rows[i] = append(canteenRow(canteen.Canteen), formatFloat(canteen.Distance, 3))
+	}
+	return writeStructured(w, r.format, records, append(canteenHeader, "distance_km"), rows, false)
+}
+
+//RenderCanteenMatches writes the results of a search
+func (r structuredRenderer) RenderCanteenMatches(w io.Writer, matches []CanteenMatch) error {
+	records := make([]interface{}, len(matches))
+	rows := make([][]string, len(matches))
+	for i, match := range matches {
+		records[i] = match
+		rows[i] = append(canteenRow(match.Canteen), formatFloat(match.Score, 2))
+	}
+	return writeStructured(w, r.format, records, append(canteenHeader, "score"), rows, false)
+}
+
+//RenderCities writes a list of cities with their number of canteens
+func (r structuredRenderer) RenderCities(w io.Writer, cities []CityCount) error {
+	records := make([]interface{}, len(cities))
+	rows := make([][]string, len(cities))
+	for i, city := range cities {
+		records[i] = city
+		rows[i] = []string{city.City, strconv.Itoa(city.Count)}
+	}
+	return writeStructured(w, r.format, records, []string{"city", "count"}, rows, false)
+}
+
+//RenderDates writes the opening status of days together with the canteen
+func (r structuredRenderer) RenderDates(w io.Writer, canteen Canteen, dates []CanteenDate) error {
+	records := make([]interface{}, len(dates))
+	rows := make([][]string, len(dates))
+	for i, date := range dates {
+		records[i] = dateRecord{Canteen: canteen, CanteenDate: date}
+		rows[i] = []string{strconv.Itoa(canteen.ID), canteen.Name, date.Date, strconv.FormatBool(date.Closed)}
+	}
+	return writeStructured(w, r.format, records, dateHeader, rows, false)
+}
+
+//RenderMeals writes the meals of a single day, in the JSON format as object instead of an array
+func (r structuredRenderer) RenderMeals(w io.Writer, canteen Canteen, day MealsOfDay) error {
+	return r.renderDays(w, canteen, []MealsOfDay{day}, true)
+}
+
+//RenderWeek writes the meals of days
+//in the CSV format every meal is a line, days without meals have a single line without meal
+func (r structuredRenderer) RenderWeek(w io.Writer, canteen Canteen, days []MealsOfDay) error {
+	return r.renderDays(w, canteen, days, false)
+}
+
+//renderDays writes days with their meals and the canteen, single writes the first day as JSON object instead of an array
+func (r structuredRenderer) renderDays(w io.Writer, canteen Canteen, days []MealsOfDay, single bool) error {
+	records := make([]interface{}, len(days))
+	rows := [][]string{}
+	for i, day := range days {
+		records[i] = mealsRecord{Canteen: canteen, MealsOfDay: day}
+		dayColumns := []string{strconv.Itoa(canteen.ID), canteen.Name, day.Date, strconv.FormatBool(day.Closed), day.NextOpenDate}
+
+		if len(day.Meals) == 0 {
+			row := append(dayColumns, make([]string, len(mealHeader)-len(dayColumns)-1)...)
+			rows = append(rows, append(row, day.Error))
+			continue
+		}
+		for _, meal := range day.Meals {
+			row := append(append([]string{}, dayColumns...),
+				strconv.Itoa(meal.ID),
+				meal.Name,
+				meal.Category,
+				strings.Join(meal.Notes, "; "),
+				formatFloat(meal.Prices.Students, 2),
+				formatFloat(meal.Prices.Employees, 2),
+				formatFloat(meal.Prices.Pupils, 2),
+				formatFloat(meal.Prices.Others, 2),
+				day.Error,
+			)
+			rows = append(rows, row)
+		}
+	}
+	return writeStructured(w, r.format, records, mealHeader, rows, single)
+}
+
+var (
+	//canteenHeader are the CSV columns of a canteen
+	canteenHeader = []string{"id", "name", "city", "address", "latitude", "longitude"}
+	//mealHeader are the CSV columns of a meal together with its canteen and day
+	mealHeader = []string{"canteen_id", "canteen_name", "date", "closed", "next_open_date", "id", "name", "category", "notes", "price_students", "price_employees", "price_pupils", "price_others", "error"}
+	//dateHeader are the CSV columns of the opening status of a day together with its canteen
+	dateHeader = []string{"canteen_id", "canteen_name", "date", "closed"}
+)
+
+//canteenRow returns the CSV columns of a canteen, the coordinates are empty when they are unknown
+func canteenRow(canteen Canteen) []string {
+	row := []string{strconv.Itoa(canteen.ID), canteen.Name, canteen.City, canteen.Address, "", ""}
+	if canteen.Coordinates != nil {
+		row[4] = strconv.FormatFloat(canteen.Coordinates.Latitude, 'f', -1, 64)
+		row[5] = strconv.FormatFloat(canteen.Coordinates.Longitude, 'f', -1, 64)
+	}
+	return row
+}
+
+func formatFloat(value float64, precision int) string {
+	return strconv.FormatFloat(value, 'f', precision, 64)
+}
+
+//writeStructured writes the records as JSON or JSON Lines or the header and the rows as CSV
+func writeStructured(w io.Writer, format string, records []interface{}, header []string, rows [][]string, single bool) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if single && len(records) > 0 {
+			return encoder.Encode(records[0])
+		}
+		return encoder.Encode(records)
+	case formatJSONL:
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case formatCSV:
+		csvWriter := csv.NewWriter(w)
+		csvWriter.Write(header)
+		csvWriter.WriteAll(rows)
+		return csvWriter.Error()
+	}
+	return fmt.Errorf("%q: %w", format, ErrUnknownFormat)
+}
@@ -0,0 +1,53 @@
+package requests
+
+import (
+	"context"
+	"sync"
+)
+
+//forEach calls fn for every index from 0 to n-1 concurrently by at most parallelism workers
+//the first error of fn cancels the context given to the other calls, the remaining indices are not started and the error is returned
+//when ctx is cancelled the remaining indices are not started and the error of ctx is returned
+//fn is called at most once for every index, so it can write its result to its own index of a slice without locking
+func forEach(ctx context.Context, n int, parallelism int, fn func(ctx context.Context, i int) error) error {
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	jobs := make(chan int)
+	for worker := 0; worker < parallelism && worker < n; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := fn(workerCtx, i); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-workerCtx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -2,8 +2,11 @@ package requests
 
 import (
 	"fmt"
+	"gomensa/dateutil"
+	"io"
 	"strconv"
 	"strings"
+	"time"
 )
 
 //CanteenToString returns a human readable string for a single canteen instance
@@ -20,72 +23,107 @@ func CanteenListToString(canteens []Canteen) string {
 	return builder.String()
 }
 
-func notesToString(notes []string) string {
+//CanteenDistanceListToString returns a human readable string for a list of canteens together with their distance
+func CanteenDistanceListToString(canteens []CanteenDistance) string {
+	builder := strings.Builder{}
+	for _, canteen := range canteens {
+		builder.WriteString(fmt.Sprintf("%.2f km -> %s\n", canteen.Distance, CanteenToString(&canteen.Canteen)))
+	}
+	return builder.String()
+}
+
+//CanteenMatchListToString returns a human readable string for a list of search results, the best match first
+func CanteenMatchListToString(matches []CanteenMatch) string {
+	builder := strings.Builder{}
+	for i, match := range matches {
+		builder.WriteString(fmt.Sprintf("%d. %s\n", i+1, CanteenToString(&match.Canteen)))
+	}
+	return builder.String()
+}
+
+//CityListToString returns a human readable string for a list of cities with the number of canteens in each city
+func CityListToString(cities []CityCount) string {
+	builder := strings.Builder{}
+	for _, city := range cities {
+		builder.WriteString(fmt.Sprintf("%s (%d)\n", city.City, city.Count))
+	}
+	return builder.String()
+}
+
+//notesToString returns the notes of a meal, with the Color option vegetarian and vegan notes are green
+func notesToString(notes []string, options RenderOptions) string {
 	builder := strings.Builder{}
 
 	for _, note := range notes {
-		builder.WriteString(fmt.Sprintf("\t\t- %s\n", note))
+		line := "- " + note
+		if veggieRegex.MatchString(note) {
+			line = options.style(line, ansiGreen)
+		}
+		builder.WriteString(fmt.Sprintf("\t\t%s\n", line))
 	}
 	return builder.String()
 }
 
-func priceToString(price prices, showOnlyStudent bool, showOnlyEmployees bool, showOnlyOthers bool, showOnlyPupils bool, seperator string) string {
+//priceToString returns the prices of a meal, when group is not AllPrices only the price of this group
+func priceToString(price prices, group PriceGroup, options RenderOptions) string {
 	builder := strings.Builder{}
+	amount := func(value float64) string {
+		return options.style(fmt.Sprintf("%0.2f€", value), ansiYellow)
+	}
 
-	if showOnlyStudent == true {
-		builder.WriteString("\n\tPrice:\n")
-		builder.WriteString(fmt.Sprintf("\t\t- students: %0.2f€", price.Students))
+	switch group {
+	case PriceStudents:
+		builder.WriteString("\n\t" + options.style("Price:", ansiCyan) + "\n")
+		builder.WriteString(fmt.Sprintf("\t\t- students: %s", amount(price.Students)))
 		return builder.String()
-
-	} else if showOnlyEmployees == true {
-		builder.WriteString("\n\tPrice:\n")
-		builder.WriteString(fmt.Sprintf("\t\t- employees: %0.2f€", price.Employees))
+	case PriceEmployees:
+		builder.WriteString("\n\t" + options.style("Price:", ansiCyan) + "\n")
+		builder.WriteString(fmt.Sprintf("\t\t- employees: %s", amount(price.Employees)))
 		return builder.String()
-
-	} else if showOnlyOthers == true {
-		builder.WriteString("\n\tPrice:\n")
-		builder.WriteString(fmt.Sprintf("\t\t- others: %0.2f€", price.Others))
+	case PriceOthers:
+		builder.WriteString("\n\t" + options.style("Price:", ansiCyan) + "\n")
+		builder.WriteString(fmt.Sprintf("\t\t- others: %s", amount(price.Others)))
 		return builder.String()
-
-	} else if showOnlyPupils == true {
-		builder.WriteString("\n\tPrice:\n")
-		builder.WriteString(fmt.Sprintf("\t\t- pupils: %0.2f€", price.Pupils))
+	case PricePupils:
+		builder.WriteString("\n\t" + options.style("Price:", ansiCyan) + "\n")
+		builder.WriteString(fmt.Sprintf("\t\t- pupils: %s", amount(price.Pupils)))
 		return builder.String()
 	}
 
-	builder.WriteString("\n\tPrices:\n")
-	builder.WriteString(fmt.Sprintf("\t\t- students: %0.2f€\n", price.Students))
+	builder.WriteString("\n\t" + options.style("Prices:", ansiCyan) + "\n")
+	builder.WriteString(fmt.Sprintf("\t\t- students: %s\n", amount(price.Students)))
 
 	//only show pupils value, when its not 0
 	if price.Pupils != 0.0 {
-		builder.WriteString(fmt.Sprintf("\t\t- pupils: %0.2f€\n", price.Pupils))
+		builder.WriteString(fmt.Sprintf("\t\t- pupils: %s\n", amount(price.Pupils)))
 	}
 
-	builder.WriteString(fmt.Sprintf("\t\t- employees: %0.2f€\n", price.Employees))
-	builder.WriteString(fmt.Sprintf("\t\t- others: %0.2f€\n", price.Others))
+	builder.WriteString(fmt.Sprintf("\t\t- employees: %s\n", amount(price.Employees)))
+	builder.WriteString(fmt.Sprintf("\t\t- others: %s\n", amount(price.Others)))
 	return builder.String()
 }
 
 //CanteenMealToString returns a human readable string for a single canteenmeal instance
-func CanteenMealToString(meal *CanteenMeal, showPrice bool, showCategory bool, showNotes bool, showOnlyStudent bool, showOnlyEmployees bool, showOnlyOthers bool, showOnlyPupils bool) string {
+//with the Color option the name is bold, the category cyan and the prices yellow
+func CanteenMealToString(meal *CanteenMeal, options RenderOptions) string {
 	builder := strings.Builder{}
 
-	builder.WriteString(fmt.Sprintf("Meal: %s", meal.Name))
+	builder.WriteString(fmt.Sprintf("Meal: %s", options.style(meal.Name, ansiBold)))
 
-	if showCategory {
-		if !showNotes && !showPrice {
-			builder.WriteString(fmt.Sprintf("\n\tCategorie:\n\t\t- %s\n", meal.Category))
-		} else {
-			builder.WriteString(fmt.Sprintf("\n\tCategorie:\n\t\t- %s", meal.Category))
+	if options.ShowCategory {
+		category := fmt.Sprintf("\n\t%s\n\t\t%s", options.style("Categorie:", ansiCyan), options.style("- "+meal.Category, ansiCyan))
+		if !options.ShowNotes && !options.ShowPrice {
+			category += "\n"
 		}
+		builder.WriteString(category)
 	}
 
-	if showNotes {
-		builder.WriteString(fmt.Sprintf("\n\tNotes:\n%s", notesToString(meal.Notes)))
+	if options.ShowNotes {
+		builder.WriteString(fmt.Sprintf("\n\t%s\n%s", options.style("Notes:", ansiCyan), notesToString(meal.Notes, options)))
 	}
 
-	if showPrice {
-		builder.WriteString(fmt.Sprintf("%s", priceToString(meal.Prices, showOnlyStudent, showOnlyEmployees, showOnlyOthers, showOnlyPupils, " ")))
+	if options.ShowPrice {
+		builder.WriteString(priceToString(meal.Prices, options.PriceGroup, options))
 	}
 
 	builder.WriteString("\n")
@@ -93,39 +131,61 @@ func CanteenMealToString(meal *CanteenMeal, showPrice bool, showCategory bool, s
 }
 
 //CanteenMealListToString returns a human readable string for a list if canteenmeals
-func CanteenMealListToString(canteenDate CanteenDate, meals []CanteenMeal, canteen *Canteen, showPrice, showNotes, showCategory, showOnlyStudent bool, showOnlyEmployees bool, showOnlyOthers bool, showOnlyPupils bool) string {
+func CanteenMealListToString(canteenDate CanteenDate, meals []CanteenMeal, canteen *Canteen, options RenderOptions) string {
 	builder := strings.Builder{}
-	builder.WriteString(fmt.Sprintf("%s meals for date: %s:\n", canteen.Name, canteenDate.Date))
+	builder.WriteString(options.style(fmt.Sprintf("%s meals for date: %s:", canteen.Name, canteenDate.Date), ansiBold) + "\n")
+	if canteenDate.Closed {
+		builder.WriteString(options.style("closed", ansiRed) + "\n")
+	}
 	for i, meal := range meals {
-		builder.WriteString(strconv.Itoa(i+1) + " " + CanteenMealToString(&meal, showPrice, showCategory, showNotes, showOnlyStudent, showOnlyEmployees, showOnlyOthers, showOnlyPupils))
+		builder.WriteString(strconv.Itoa(i+1) + " " + CanteenMealToString(&meal, options))
 	}
 	return builder.String()
 }
 
 //CanteenMealWeekListToString returns a human readable string for a whole week of meals
-func CanteenMealWeekListToString(canteenWeek []CanteenDate, mealweek [][]CanteenMeal, canteen *Canteen, showPrice, showNotes, showCategory, showOnlyStudent, showOnlyEmployees, showOnlyOthers, showOnlyPupils bool) string {
+func CanteenMealWeekListToString(days []MealsOfDay, canteen *Canteen, options RenderOptions) string {
 	builder := strings.Builder{}
 
-	if len(canteenWeek) > 0 {
-		builder.WriteString(fmt.Sprintf("%s meals for dates: %s - %s\n", canteen.Name, canteenWeek[0].Date, canteenWeek[len(canteenWeek)-1].Date))
+	if len(days) > 0 {
+		builder.WriteString(options.style(fmt.Sprintf("%s meals for dates: %s - %s", canteen.Name, days[0].Date, days[len(days)-1].Date), ansiBold) + "\n")
 	}
 
-	for i := range mealweek {
-		if i == 0 {
-			builder.WriteString("-> " + canteenWeek[i].Date + ":\n")
-		} else {
-			builder.WriteString("\n-> " + canteenWeek[i].Date + ":\n")
+	for i, day := range days {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(options.style("-> "+day.Date+":", ansiBold) + "\n")
+
+		if day.Closed {
+			builder.WriteString(options.style("closed", ansiRed) + "\n")
+			continue
 		}
 
-		for j, meal := range mealweek[i] {
-			builder.WriteString(fmt.Sprintf("%d %s", j+1, CanteenMealToString(&meal, showPrice, showCategory, showNotes, showOnlyStudent, showOnlyEmployees, showOnlyOthers, showOnlyPupils)))
+		if day.Error != "" {
+			builder.WriteString(options.style("Could not retrieve the meals of this day!", ansiRed) + "\n")
+		}
+
+		for j, meal := range day.Meals {
+			builder.WriteString(fmt.Sprintf("%d %s", j+1, CanteenMealToString(&meal, options)))
 		}
 	}
 	return builder.String()
 }
 
+//ClosedToString returns a message telling that the canteen is closed on a day together with the next open day if it is known
+func ClosedToString(day MealsOfDay) string {
+	now := time.Now()
+	closedOn := dateutil.DescribeDay(day.Date, now)
+	if day.NextOpenDate == "" {
+		return fmt.Sprintf("The mensa is closed %s and the next open day is not known yet.", closedOn)
+	}
+	return fmt.Sprintf("The mensa is closed %s, the next open day is %s.", closedOn, dateutil.Describe(day.NextOpenDate, now))
+}
+
 //CanteenDateOpenedToString returns a string date representation of a canteen date with its opening information
-func CanteenDateOpenedToString(canteenDate *CanteenDate, canteenName string, showWeek bool) string {
+//with the Color option closed is red and open green
+func CanteenDateOpenedToString(canteenDate *CanteenDate, canteenName string, showWeek bool, options RenderOptions) string {
 	builder := strings.Builder{}
 
 	if showWeek == false && len(canteenName) > 1 && len(canteenDate.Date) > 1 {
@@ -135,22 +195,86 @@ func CanteenDateOpenedToString(canteenDate *CanteenDate, canteenName string, sho
 
 	builder.WriteString(" - " + canteenDate.Date)
 	if canteenDate.Closed {
-		builder.WriteString(" -> closed")
+		builder.WriteString(" -> " + options.style("closed", ansiRed))
 	} else {
-		builder.WriteString(" -> open")
+		builder.WriteString(" -> " + options.style("open", ansiGreen))
 	}
 	return builder.String()
 }
 
 //CanteenDateListToString returns a prettified version of a list of canteen dates
-func CanteenDateListToString(canteenDates []CanteenDate, canteenName string) string {
+func CanteenDateListToString(canteenDates []CanteenDate, canteenName string, options RenderOptions) string {
 	builder := strings.Builder{}
 
 	builder.WriteString(canteenName)
 	builder.WriteString(" is open or closed on the following dates:\n")
 
 	for _, date := range canteenDates {
-		builder.WriteString(fmt.Sprintf("\t%s\n", CanteenDateOpenedToString(&date, "", false)))
+		builder.WriteString(fmt.Sprintf("\t%s\n", CanteenDateOpenedToString(&date, "", false, options)))
 	}
 	return builder.String()
 }
+
+//textRenderer writes the human readable output of the prettyfier, with the Color option the meals and dates are colored
+type textRenderer struct {
+	options RenderOptions
+}
+
+func newTextRenderer(options RenderOptions) Renderer {
+	return textRenderer{options: options}
+}
+
+//RenderCanteen writes a single canteen
+func (r textRenderer) RenderCanteen(w io.Writer, canteen Canteen) error {
+	return writeText(w, CanteenToString(&canteen))
+}
+
+//RenderCanteens writes a list of canteens
+func (r textRenderer) RenderCanteens(w io.Writer, canteens []Canteen) error {
+	return writeText(w, CanteenListToString(canteens))
+}
+
+//RenderCanteenDistances writes a list of canteens with their distance
+func (r textRenderer) RenderCanteenDistances(w io.Writer, canteens []CanteenDistance) error {
+	return writeText(w, CanteenDistanceListToString(canteens))
+}
+
+//RenderCanteenMatches writes the results of a search or a message when nothing matched
+func (r textRenderer) RenderCanteenMatches(w io.Writer, matches []CanteenMatch) error {
+	if len(matches) == 0 {
+		return writeText(w, "No mensa matches your search.")
+	}
+	return writeText(w, CanteenMatchListToString(matches))
+}
+
+//RenderCities writes a list of cities with their number of canteens
+func (r textRenderer) RenderCities(w io.Writer, cities []CityCount) error {
+	return writeText(w, CityListToString(cities))
+}
+
+//RenderMeals writes the meals of a single day, for a closed day the next open day is written instead
+func (r textRenderer) RenderMeals(w io.Writer, canteen Canteen, day MealsOfDay) error {
+	if day.Closed {
+		return writeText(w, r.options.style(ClosedToString(day), ansiRed))
+	}
+	return writeText(w, CanteenMealListToString(CanteenDate{Date: day.Date}, day.Meals, &canteen, r.options))
+}
+
+//RenderWeek writes the meals of multiple days
+func (r textRenderer) RenderWeek(w io.Writer, canteen Canteen, days []MealsOfDay) error {
+	return writeText(w, CanteenMealWeekListToString(days, &canteen, r.options))
+}
+
+//RenderDates writes the opening status of days
+func (r textRenderer) RenderDates(w io.Writer, canteen Canteen, dates []CanteenDate) error {
+	if len(dates) == 1 {
+		return writeText(w, CanteenDateOpenedToString(&dates[0], canteen.Name, false, r.options))
+	}
+	return writeText(w, CanteenDateListToString(dates, canteen.Name, r.options))
+}
+
+//writeText writes text followed by an empty line
+func writeText(w io.Writer, text string) error {
+	_, err := fmt.Fprintln(w, text)
+	return err
+}
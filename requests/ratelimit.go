@@ -0,0 +1,69 @@
+package requests
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+//RateLimiter is a token bucket which limits the requests of a client to a number of requests per second
+//it is safe for concurrent use, so all requests of a client, also the concurrent ones, share the same limit
+type RateLimiter struct {
+	mutex sync.Mutex
+	//perSecond is the number of tokens added to the bucket every second
+	perSecond float64
+	//burst is the size of the bucket, so the number of requests which can be made at once
+	burst float64
+	//tokens are the available tokens, it is negative when callers already reserved future tokens
+	tokens float64
+	//last is the time the tokens were updated
+	last time.Time
+}
+
+//NewRateLimiter returns a rate limiter allowing perSecond requests per second on average and up to burst requests at once
+//a burst below 1 is treated as 1
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		perSecond: perSecond,
+		burst:     float64(burst),
+		tokens:    float64(burst),
+		last:      time.Now(),
+	}
+}
+
+//Wait blocks until the caller is allowed to make a request or ctx is cancelled
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l.perSecond <= 0 {
+		return nil
+	}
+
+	l.mutex.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.perSecond)
+	l.last = now
+
+	//the token is reserved right away, so concurrent callers queue up behind each other
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.perSecond * float64(time.Second))
+	}
+	l.mutex.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, delay); err != nil {
+		//the request is not made, so the reserved token is given back
+		l.mutex.Lock()
+		l.tokens++
+		l.mutex.Unlock()
+		return err
+	}
+	return nil
+}
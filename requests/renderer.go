@@ -0,0 +1,118 @@
+package requests
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//PriceGroup is a group of people with its own price for a meal
+type PriceGroup string
+
+const (
+	//AllPrices shows the prices of all groups
+	AllPrices PriceGroup = ""
+	//PriceStudents only shows the price for students
+	PriceStudents PriceGroup = "students"
+	//PriceEmployees only shows the price for employees
+	PriceEmployees PriceGroup = "employees"
+	//PricePupils only shows the price for pupils
+	PricePupils PriceGroup = "pupils"
+	//PriceOthers only shows the price for everyone else
+	PriceOthers PriceGroup = "others"
+)
+
+//RenderOptions decides which information about meals is rendered, the zero value only renders the names of the meals
+//the structured formats always contain all information and ignore the options
+type RenderOptions struct {
+	ShowPrice    bool
+	ShowNotes    bool
+	ShowCategory bool
+	//PriceGroup only shows the price of this group when ShowPrice is set, AllPrices shows every price
+	PriceGroup PriceGroup
+	//Width is the maximal width of a line of the table format, 0 uses 100 columns
+	Width int
+	//Color colors the text format with ANSI escape sequences, see UseColor to decide whether the output should be colored
+	Color bool
+	//Standalone writes a complete page with embedded CSS in the html format instead of a fragment to embed into another page
+	Standalone bool
+}
+
+//Renderer writes mensas, dates and meals to w in an output format
+//canteen is the mensa the dates and meals belong to, days are built with NewMealsOfDay and NewMealsOfDays
+type Renderer interface {
+	RenderCanteen(w io.Writer, canteen Canteen) error
+	RenderCanteens(w io.Writer, canteens []Canteen) error
+	RenderCanteenDistances(w io.Writer, canteens []CanteenDistance) error
+	RenderCanteenMatches(w io.Writer, matches []CanteenMatch) error
+	RenderCities(w io.Writer, cities []CityCount) error
+	RenderMeals(w io.Writer, canteen Canteen, day MealsOfDay) error
+	RenderWeek(w io.Writer, canteen Canteen, days []MealsOfDay) error
+	RenderDates(w io.Writer, canteen Canteen, dates []CanteenDate) error
+}
+
+//RendererFactory returns a renderer using the given options
+type RendererFactory func(options RenderOptions) Renderer
+
+//ErrUnknownFormat is returned when no renderer is registered for an output format
+var ErrUnknownFormat = errors.New("unknown output format")
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]RendererFactory{}
+)
+
+func init() {
+	RegisterRenderer(formatText, newTextRenderer)
+	RegisterRenderer(formatJSON, newStructuredRenderer(formatJSON))
+	RegisterRenderer(formatJSONL, newStructuredRenderer(formatJSONL))
+	RegisterRenderer(formatCSV, newStructuredRenderer(formatCSV))
+	RegisterRenderer(formatTable, newTableRenderer)
+	RegisterRenderer(formatMarkdown, newMarkdownRenderer)
+	RegisterRenderer(formatHTML, newHTMLRenderer)
+}
+
+//RegisterRenderer makes an output format available under its name, f.e. to be selected with the output flag
+//it panics when the name is already registered or the factory is nil
+func RegisterRenderer(name string, factory RendererFactory) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+
+	name = strings.ToLower(name)
+	if factory == nil {
+		panic("requests: RegisterRenderer factory for " + name + " is nil")
+	}
+	if _, ok := renderers[name]; ok {
+		panic("requests: RegisterRenderer called twice for " + name)
+	}
+	renderers[name] = factory
+}
+
+//NewRenderer returns the renderer of the output format with the given name, f.e. json
+//returns ErrUnknownFormat when no renderer is registered with this name
+func NewRenderer(name string, options RenderOptions) (Renderer, error) {
+	renderersMu.RLock()
+	factory, ok := renderers[strings.ToLower(strings.TrimSpace(name))]
+	renderersMu.RUnlock()
+
+	if ok == false {
+		return nil, fmt.Errorf("%q: %w, expected one of %s", name, ErrUnknownFormat, strings.Join(Renderers(), ", "))
+	}
+	return factory(options), nil
+}
+
+//Renderers returns the sorted names of all registered output formats
+func Renderers() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,110 @@
+package requests
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//RetryPolicy decides how often and how long a client waits before repeating a failed request
+//only GET requests are made by the client, so every request can be repeated safely
+type RetryPolicy struct {
+	//MaxAttempts is the maximal number of attempts of a request including the first one, a value below 2 disables retries
+	MaxAttempts int
+	//BaseDelay is the delay before the first retry, it doubles with every further retry
+	BaseDelay time.Duration
+	//MaxDelay limits the delay between two attempts, also the one requested by a Retry-After header
+	MaxDelay time.Duration
+}
+
+//DefaultRetryPolicy is the retry policy of a client created with NewClient
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+//retryable reports whether a failed request is worth repeating, these are network errors, 429 Too Many Requests and temporary server errors
+func retryable(ctx context.Context, err error) bool {
+	//the caller gave up, so there is nobody waiting for another attempt
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	//a response which is no JSON will not become JSON by asking again
+	if errors.Is(err, ErrInvalidResponse) || errors.Is(err, errNotModified) {
+		return false
+	}
+	return true
+}
+
+//delay returns how long to wait before the given retry, the first retry has the number 1
+//a Retry-After duration of the server is used when it is given, otherwise the delay grows exponentially with a random jitter
+//a BaseDelay of 0 retries without waiting
+func (p RetryPolicy) delay(retry int, retryAfter time.Duration) time.Duration {
+	delay := retryAfter
+	if delay <= 0 {
+		if p.BaseDelay <= 0 {
+			return 0
+		}
+
+		limit := p.MaxDelay
+		if limit <= 0 {
+			limit = math.MaxInt64
+		}
+		//the delay is limited before shifting, because BaseDelay << shift can overflow to any value
+		shift := uint(retry - 1)
+		if shift >= 62 || p.BaseDelay > limit>>shift {
+			delay = limit
+		} else {
+			delay = p.BaseDelay << shift
+		}
+		//waiting between 50% and 100% of the delay keeps concurrent clients from retrying at the same time
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+//parseRetryAfter parses the Retry-After header which is either a number of seconds or a HTTP date, returns 0 when the header is missing or invalid
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+//sleep waits for the duration or until ctx is cancelled
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,175 @@
+package requests
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	//nameWeight, cityWeight and addressWeight rate a match in the name higher than one in the city or the address
+	nameWeight    = 3.0
+	cityWeight    = 2.0
+	addressWeight = 1.0
+
+	//the quality of a match of a single search term with a word
+	exactMatch     = 1.0
+	prefixMatch    = 0.8
+	substringMatch = 0.6
+	typoMatch      = 0.4
+)
+
+//CanteenMatch is a canteen found by SearchCanteens together with its score, a higher score is a better match
+type CanteenMatch struct {
+	Canteen Canteen `json:"canteen"`
+	Score   float64 `json:"score"`
+}
+
+//foldReplacer writes umlauts and other special characters the way they are typed on a keyboard without them, f.e. ä -> ae and ß -> ss
+var foldReplacer = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"á", "a", "à", "a", "â", "a", "é", "e", "è", "e", "ê", "e",
+	"í", "i", "ì", "i", "î", "i", "ó", "o", "ò", "o", "ô", "o",
+	"ú", "u", "ù", "u", "û", "u", "ç", "c", "ñ", "n",
+)
+
+//plainReplacer drops the dots of umlauts, so "sud" also finds "Süd" when the user did not type "sued"
+var plainReplacer = strings.NewReplacer("ä", "a", "ö", "o", "ü", "u")
+
+//SearchCanteens returns the canteens whose name, city or address match the query sorted by their score, the best match first
+//the search ignores the case and umlauts, so "muenchen" finds "München", and tolerates small typos
+//every word of the query has to match, an empty query matches no canteen
+func SearchCanteens(canteens []Canteen, query string) []CanteenMatch {
+	terms := searchWords(query)
+	matches := []CanteenMatch{}
+	if len(terms) == 0 {
+		return matches
+	}
+
+	for _, canteen := range canteens {
+		fields := []struct {
+			words  []string
+			weight float64
+		}{
+			{canteenWords(canteen.Name), nameWeight},
+			{canteenWords(canteen.City), cityWeight},
+			{canteenWords(canteen.Address), addressWeight},
+		}
+
+		score := 0.0
+		for _, term := range terms {
+			best := 0.0
+			for _, field := range fields {
+				if quality := matchWords(term, field.words) * field.weight; quality > best {
+					best = quality
+				}
+			}
+			//a term which matches nowhere excludes the canteen
+			if best == 0 {
+				score = 0
+				break
+			}
+			score += best
+		}
+
+		if score > 0 {
+			matches = append(matches, CanteenMatch{Canteen: canteen, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+//searchWords returns the folded lower case words of text, f.e. "Mensa Süd, Leipzig" -> [mensa sued leipzig]
+func searchWords(text string) []string {
+	text = foldReplacer.Replace(strings.ToLower(text))
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsLetter(r) == false && unicode.IsDigit(r) == false
+	})
+}
+
+//canteenWords returns the words of a canteen field, words with umlauts are contained with both spellings, f.e. "Süd" -> [sued sud]
+func canteenWords(text string) []string {
+	words := searchWords(text)
+	for _, word := range searchWords(plainReplacer.Replace(strings.ToLower(text))) {
+		if containsString(words, word) == false {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+//matchWords returns the quality of the best match of term with one of the words, 0 when it matches none
+func matchWords(term string, words []string) float64 {
+	best := 0.0
+	for _, word := range words {
+		quality := 0.0
+		switch {
+		case word == term:
+			quality = exactMatch
+		case strings.HasPrefix(word, term):
+			quality = prefixMatch
+		case strings.Contains(word, term):
+			quality = substringMatch
+		case levenshtein(term, word) <= allowedTypos(term):
+			quality = typoMatch
+		}
+		if quality > best {
+			best = quality
+		}
+	}
+	return best
+}
+
+//allowedTypos returns how many typos are tolerated in a search term, short terms have to match exactly
+func allowedTypos(term string) int {
+	switch length := len([]rune(term)); {
+	case length >= 8:
+		return 2
+	case length >= 4:
+		return 1
+	}
+	return 0
+}
+
+//levenshtein returns the number of single character insertions, deletions and substitutions needed to turn a into b
+func levenshtein(a string, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(runesA); i++ {
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+		previous, current = current, previous
+	}
+	return previous[len(runesB)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,77 @@
+package requests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+//ColorMode decides when the text output is colored
+type ColorMode string
+
+const (
+	//ColorAuto colors the output when it is written to a terminal and the NO_COLOR environment variable is not set
+	ColorAuto ColorMode = "auto"
+	//ColorAlways always colors the output, also when it is written to a file or a pipe
+	ColorAlways ColorMode = "always"
+	//ColorNever never colors the output
+	ColorNever ColorMode = "never"
+)
+
+//ErrUnknownColorMode is returned when a color mode is not always, never or auto
+var ErrUnknownColorMode = errors.New("unknown color mode, expected always, never or auto")
+
+//ANSI escape sequences of the styles used in the colored text output
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+//veggieRegex matches the notes of vegetarian and vegan meals
+var veggieRegex = regexp.MustCompile(`(?i)vegetar|vegan|fleischlos`)
+
+//ParseColorMode returns the color mode with the given name, an empty name is ColorAuto
+func ParseColorMode(name string) (ColorMode, error) {
+	switch mode := ColorMode(strings.ToLower(strings.TrimSpace(name))); mode {
+	case "":
+		return ColorAuto, nil
+	case ColorAuto, ColorAlways, ColorNever:
+		return mode, nil
+	}
+	return "", fmt.Errorf("%q: %w", name, ErrUnknownColorMode)
+}
+
+//UseColor reports whether output written to out should be colored
+//in the auto mode the output is only colored when out is a terminal, the NO_COLOR environment variable is empty and TERM is not dumb
+func UseColor(mode ColorMode, out *os.File) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := out.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+//style returns text in the given ANSI style when the Color option is set, otherwise text as it is
+//the prettyfier styles the pieces it writes, so names and notes of meals are never mistaken for headings
+func (o RenderOptions) style(text string, style string) string {
+	if o.Color == false || text == "" {
+		return text
+	}
+	return style + text + ansiReset
+}
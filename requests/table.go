@@ -0,0 +1,423 @@
+package requests
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	//formatTable prints meals and canteens as aligned columns
+	formatTable = "table"
+	//defaultTableWidth is the width of a table line when RenderOptions.Width is not set
+	defaultTableWidth = 100
+	//minWrapWidth is the width to which a column with long texts is shrunk at most before the table gets wider than the line
+	minWrapWidth = 12
+	//columnSeparator separates the cells of a row
+	columnSeparator = " | "
+)
+
+//table is a list of rows with aligned columns, long texts are wrapped to multiple lines to fit into the width of a line
+type table struct {
+	header []string
+	//rightAligned columns are aligned to the right, f.e. numbers and prices
+	rightAligned []bool
+	//wrapped columns are shrunk and wrapped when the table is wider than the line
+	wrapped []bool
+	rows    [][]string
+}
+
+//tableRenderer writes meals and canteens as aligned tables
+type tableRenderer struct {
+	options RenderOptions
+}
+
+func newTableRenderer(options RenderOptions) Renderer {
+	if options.Width <= 0 {
+		options.Width = defaultTableWidth
+	}
+	return tableRenderer{options: options}
+}
+
+//RenderCanteen writes a single canteen as table with one row
+func (r tableRenderer) RenderCanteen(w io.Writer, canteen Canteen) error {
+	return r.RenderCanteens(w, []Canteen{canteen})
+}
+
+//RenderCanteens writes a table with the ID, name, city and address of the canteens
+func (r tableRenderer) RenderCanteens(w io.Writer, canteens []Canteen) error {
+	t := table{
+		header:       []string{"ID", "Name", "City", "Address"},
+		rightAligned: []bool{true, false, false, false},
+		wrapped:      []bool{false, true, true, true},
+	}
+	for _, canteen := range canteens {
+		t.rows = append(t.rows, []string{strconv.Itoa(canteen.ID), canteen.Name, canteen.City, canteen.Address})
+	}
+	return t.write(w, r.options.Width)
+}
+
+//RenderCanteenDistances writes a table of canteens with their distance in the first column
+func (r tableRenderer) RenderCanteenDistances(w io.Writer, canteens []CanteenDistance) error {
+	t := table{
+		header:       []string{"Distance", "ID", "Name", "City", "Address"},
+		rightAligned: []bool{true, true, false, false, false},
+		wrapped:      []bool{false, false, true, true, true},
+	}
+	for _, canteen := range canteens {
+		t.rows = append(t.rows, []string{fmt.Sprintf("%.2f km", canteen.Distance), strconv.Itoa(canteen.Canteen.ID), canteen.Canteen.Name, canteen.Canteen.City, canteen.Canteen.Address})
+	}
+	return t.write(w, r.options.Width)
+}
+
+//RenderCanteenMatches writes a table of the search results, the best match first
+func (r tableRenderer) RenderCanteenMatches(w io.Writer, matches []CanteenMatch) error {
+	if len(matches) == 0 {
+		return writeText(w, "No mensa matches your search.")
+	}
+
+	t := table{
+		header:       []string{"#", "ID", "Name", "City", "Address"},
+		rightAligned: []bool{true, true, false, false, false},
+		wrapped:      []bool{false, false, true, true, true},
+	}
+	for i, match := range matches {
+		t.rows = append(t.rows, []string{strconv.Itoa(i + 1), strconv.Itoa(match.Canteen.ID), match.Canteen.Name, match.Canteen.City, match.Canteen.Address})
+	}
+	return t.write(w, r.options.Width)
+}
+
+//RenderCities writes a table of the cities with their number of canteens
+func (r tableRenderer) RenderCities(w io.Writer, cities []CityCount) error {
+	t := table{
+		header:       []string{"City", "Mensas"},
+		rightAligned: []bool{false, true},
+		wrapped:      []bool{true, false},
+	}
+	for _, city := range cities {
+		t.rows = append(t.rows, []string{city.City, strconv.Itoa(city.Count)})
+	}
+	return t.write(w, r.options.Width)
+}
+
+//RenderMeals writes the meals of a single day as table below the name of the canteen and the day
+func (r tableRenderer) RenderMeals(w io.Writer, canteen Canteen, day MealsOfDay) error {
+	if day.Closed {
+		return writeText(w, ClosedToString(day))
+	}
+	if _, err := fmt.Fprintf(w, "%s - %s\n\n", canteen.Name, weekdayOf(day.Date)); err != nil {
+		return err
+	}
+	return r.renderDay(w, day)
+}
+
+//RenderWeek writes the meals of every day as table below a header with the weekday and the date
+func (r tableRenderer) RenderWeek(w io.Writer, canteen Canteen, days []MealsOfDay) error {
+	if len(days) > 0 {
+		if _, err := fmt.Fprintf(w, "%s meals for dates: %s - %s\n\n", canteen.Name, days[0].Date, days[len(days)-1].Date); err != nil {
+			return err
+		}
+	}
+
+	for _, day := range days {
+		if _, err := fmt.Fprintf(w, "== %s ==\n", weekdayOf(day.Date)); err != nil {
+			return err
+		}
+		if err := r.renderDay(w, day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//renderDay writes the table of the meals of a day or why there are no meals followed by an empty line
+func (r tableRenderer) renderDay(w io.Writer, day MealsOfDay) error {
+	switch {
+	case day.Closed:
+		return writeText(w, "closed\n")
+	case day.Error != "":
+		return writeText(w, "Could not retrieve the meals of this day!\n")
+	case len(day.Meals) == 0:
+		return writeText(w, "No meals\n")
+	}
+	return r.mealTable(day.Meals).write(w, r.options.Width)
+}
+
+//mealTable returns the table of the meals with their category and prices, the notes are only contained when ShowNotes is set
+//when a PriceGroup is set only its price is contained, otherwise the prices for students, employees and others
+func (r tableRenderer) mealTable(meals []CanteenMeal) table {
+	t := table{
+		header:       []string{"#", "Category", "Meal"},
+		rightAligned: []bool{true, false, false},
+		wrapped:      []bool{false, true, true},
+	}
+
+	priceGroups := shownPriceGroups(r.options.PriceGroup)
+	for _, group := range priceGroups {
+		t.header = append(t.header, priceHeaders[group])
+		t.rightAligned = append(t.rightAligned, true)
+		t.wrapped = append(t.wrapped, false)
+	}
+
+	if r.options.ShowNotes {
+		t.header = append(t.header, "Notes")
+		t.rightAligned = append(t.rightAligned, false)
+		t.wrapped = append(t.wrapped, true)
+	}
+
+	for i, meal := range meals {
+		row := []string{strconv.Itoa(i + 1), meal.Category, meal.Name}
+		for _, group := range priceGroups {
+			row = append(row, priceCell(meal.Prices, group))
+		}
+		if r.options.ShowNotes {
+			row = append(row, strings.Join(meal.Notes, ", "))
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t
+}
+
+//RenderDates writes a table of the days with their weekday and opening status
+func (r tableRenderer) RenderDates(w io.Writer, canteen Canteen, dates []CanteenDate) error {
+	if _, err := fmt.Fprintf(w, "%s\n\n", canteen.Name); err != nil {
+		return err
+	}
+
+	t := table{
+		header:       []string{"Date", "Status"},
+		rightAligned: []bool{false, false},
+		wrapped:      []bool{false, false},
+	}
+	for _, date := range dates {
+		t.rows = append(t.rows, []string{weekdayOf(date.Date), openingStatus(date.Closed)})
+	}
+	return t.write(w, r.options.Width)
+}
+
+//shownPriceGroups returns the groups whose prices are shown in a table of meals, all groups except pupils for AllPrices
+func shownPriceGroups(group PriceGroup) []PriceGroup {
+	if group != AllPrices {
+		return []PriceGroup{group}
+	}
+	return []PriceGroup{PriceStudents, PriceEmployees, PriceOthers}
+}
+
+//priceHeaders are the titles of the price columns
+var priceHeaders = map[PriceGroup]string{
+	PriceStudents:  "Students",
+	PriceEmployees: "Employees",
+	PricePupils:    "Pupils",
+	PriceOthers:    "Others",
+}
+
+//priceCell returns the price of the group with two decimals or "-" when the canteen did not publish it
+func priceCell(price prices, group PriceGroup) string {
+	var value float64
+	switch group {
+	case PriceStudents:
+		value = price.Students
+	case PriceEmployees:
+		value = price.Employees
+	case PricePupils:
+		value = price.Pupils
+	case PriceOthers:
+		value = price.Others
+	}
+	if value == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%0.2f€", value)
+}
+
+//weekdayOf returns the weekday and the date, f.e. "Friday 2020-01-31", or the date unchanged when it can not be parsed
+func weekdayOf(date string) string {
+	if day, err := time.Parse(dateLayout, date); err == nil {
+		return day.Weekday().String() + " " + date
+	}
+	return date
+}
+
+//write writes the header, a separator line and the rows of the table followed by an empty line
+func (t table) write(w io.Writer, width int) error {
+	widths := t.columnWidths(width)
+
+	separator := make([]string, len(widths))
+	for i, columnWidth := range widths {
+		separator[i] = strings.Repeat("-", columnWidth)
+	}
+
+	builder := strings.Builder{}
+	t.writeRow(&builder, t.header, widths)
+	builder.WriteString(strings.Join(separator, "-+-") + "\n")
+	for _, row := range t.rows {
+		t.writeRow(&builder, row, widths)
+	}
+
+	return writeText(w, builder.String())
+}
+
+//writeRow writes the cells of a row, cells which are wider than their column are wrapped to additional lines
+func (t table) writeRow(builder *strings.Builder, row []string, widths []int) {
+	cellLines := make([][]string, len(widths))
+	height := 1
+	for i := range widths {
+		cell := ""
+		if i < len(row) {
+			cell = row[i]
+		}
+		cellLines[i] = wrapText(cell, widths[i])
+		if len(cellLines[i]) > height {
+			height = len(cellLines[i])
+		}
+	}
+
+	for line := 0; line < height; line++ {
+		cells := make([]string, len(widths))
+		//empty cells at the end of a line are left out, so there are no trailing separators
+		last := 0
+		for i, lines := range cellLines {
+			text := ""
+			if line < len(lines) {
+				text = lines[line]
+			}
+			if text != "" {
+				last = i
+			}
+			padding := strings.Repeat(" ", widths[i]-displayWidth(text))
+			if t.rightAligned[i] {
+				cells[i] = padding + text
+			} else {
+				cells[i] = text + padding
+			}
+		}
+		builder.WriteString(strings.TrimRightFunc(strings.Join(cells[:last+1], columnSeparator), unicode.IsSpace) + "\n")
+	}
+}
+
+//columnWidths returns the width of every column, it is the width of the widest cell
+//when the table is wider than width the widest wrapped column is shrunk until the table fits or all wrapped columns have the minWrapWidth
+func (t table) columnWidths(width int) []int {
+	widths := make([]int, len(t.header))
+	for i, title := range t.header {
+		widths[i] = displayWidth(title)
+	}
+	for _, row := range t.rows {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			if cellWidth := displayWidth(row[i]); cellWidth > widths[i] {
+				widths[i] = cellWidth
+			}
+		}
+	}
+
+	total := (len(widths) - 1) * displayWidth(columnSeparator)
+	for _, columnWidth := range widths {
+		total += columnWidth
+	}
+
+	for total > width {
+		widest := -1
+		for i, columnWidth := range widths {
+			if t.wrapped[i] && columnWidth > minWrapWidth && (widest == -1 || columnWidth > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest == -1 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+//wrapText splits text into lines which are at most width wide, the lines are broken between words if possible
+//words which are wider than a line are split
+func wrapText(text string, width int) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for displayWidth(word) > width {
+			//fill the current line before splitting the word
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			head, tail := splitAtWidth(word, width)
+			lines = append(lines, head)
+			word = tail
+		}
+
+		switch {
+		case line == "":
+			line = word
+		case displayWidth(line)+1+displayWidth(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+//splitAtWidth splits text into a head which is at most width wide and the remaining tail, the head contains at least one rune
+func splitAtWidth(text string, width int) (string, string) {
+	headWidth := 0
+	for i, r := range text {
+		runeWidth := runeDisplayWidth(r)
+		if headWidth+runeWidth > width && i > 0 {
+			return text[:i], text[i:]
+		}
+		headWidth += runeWidth
+	}
+	return text, ""
+}
+
+//displayWidth returns the number of terminal columns text needs, combining marks need none and east asian wide characters two
+func displayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		width += runeDisplayWidth(r)
+	}
+	return width
+}
+
+//wideRanges are the ranges of east asian wide and fullwidth characters and emojis which need two terminal columns
+var wideRanges = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x115f, Stride: 1},
+		{Lo: 0x2e80, Hi: 0x303e, Stride: 1},
+		{Lo: 0x3041, Hi: 0x33ff, Stride: 1},
+		{Lo: 0x3400, Hi: 0x4dbf, Stride: 1},
+		{Lo: 0x4e00, Hi: 0x9fff, Stride: 1},
+		{Lo: 0xa000, Hi: 0xa4cf, Stride: 1},
+		{Lo: 0xac00, Hi: 0xd7a3, Stride: 1},
+		{Lo: 0xf900, Hi: 0xfaff, Stride: 1},
+		{Lo: 0xfe30, Hi: 0xfe4f, Stride: 1},
+		{Lo: 0xff00, Hi: 0xff60, Stride: 1},
+		{Lo: 0xffe0, Hi: 0xffe6, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f300, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f900, Hi: 0x1f9ff, Stride: 1},
+		{Lo: 0x20000, Hi: 0x3fffd, Stride: 1},
+	},
+}
+
+//runeDisplayWidth returns the number of terminal columns of a single rune
+func runeDisplayWidth(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf), unicode.IsControl(r):
+		return 0
+	case unicode.Is(wideRanges, r):
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,216 @@
+package requests
+
+import (
+	"fmt"
+	"gomensa/dateutil"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//TemplateData is what a template of the TemplateRenderer gets, only the fields of the current command are set
+//the meal commands set Days, the commands for a single day like mealToday additionally set Day to the first day
+type TemplateData struct {
+	//Canteen is the mensa of the meal and opening status commands and of showMensa
+	Canteen   Canteen
+	Canteens  []Canteen
+	Distances []CanteenDistance
+	Matches   []CanteenMatch
+	Cities    []CityCount
+	Day       *MealsOfDay
+	Days      []MealsOfDay
+	Dates     []CanteenDate
+	Options   RenderOptions
+}
+
+//builtinTemplates are the templates which can be used by their name instead of the template text
+var builtinTemplates = map[string]string{
+	//tmux prints the meals of the first day on a single line for status bars
+	"tmux": `{{with .Day}}{{if .Closed}}closed{{else}}{{range $i, $meal := .Meals}}{{if $i}} | {{end}}{{truncate 30 $meal.Name}} {{price $meal.Prices "students"}}{{end}}{{end}}{{end}}
+`,
+	//oneline prints every day, canteen, city or opening status on its own line
+	"oneline": `{{range .Days}}{{date "Mon 02.01." .Date}}: {{if .Closed}}closed{{else if .Error}}{{.Error}}{{else}}{{range $i, $meal := .Meals}}{{if $i}}, {{end}}{{$meal.Name}}{{end}}{{end}}
+{{end}}{{range .Dates}}{{date "Mon 02.01." .Date}}: {{if .Closed}}closed{{else}}open{{end}}
+{{end}}{{range .Canteens}}{{.ID}} {{.Name}}, {{.City}}
+{{end}}{{range .Distances}}{{printf "%.2f" .Distance}} km {{.Canteen.ID}} {{.Canteen.Name}}, {{.Canteen.City}}
+{{end}}{{range .Matches}}{{.Canteen.ID}} {{.Canteen.Name}}, {{.Canteen.City}}
+{{end}}{{range .Cities}}{{.City}} ({{.Count}})
+{{end}}`,
+	//chat prints the meals as message for chat bots with the vegetarian and vegan meals marked
+	"chat": `{{$canteen := .Canteen}}{{range .Days}}*{{$canteen.Name}}, {{relativeDate .Date}}*
+{{if .Closed}}closed
+{{else if .Error}}{{.Error}}
+{{else}}{{range .Meals}}- {{.Name}}{{if veggie .Notes}} (veggie){{end}}: {{price .Prices "students"}}
+{{end}}{{end}}
+{{end}}`,
+}
+
+//templateFuncs are the functions which can be used in the templates
+var templateFuncs = template.FuncMap{
+	//price returns the price of a group as 2.10€ or - when it is not known, f.e. {{price .Prices "students"}}
+	"price": func(price prices, group string) string {
+		return priceCell(price, PriceGroup(strings.ToLower(group)))
+	},
+	//date formats a date in the format YYYY-MM-DD with a Go time layout, f.e. {{date "Mon 02.01." .Date}}
+	"date": func(layout string, date string) string {
+		day, err := time.Parse(dateLayout, date)
+		if err != nil {
+			return date
+		}
+		return day.Format(layout)
+	},
+	//weekday returns the english name of the weekday of a date, f.e. Friday
+	"weekday": func(date string) string {
+		day, err := time.Parse(dateLayout, date)
+		if err != nil {
+			return ""
+		}
+		return day.Weekday().String()
+	},
+	//relativeDate returns today, tomorrow or the weekday and the date, f.e. Friday 2020-01-31
+	"relativeDate": func(date string) string {
+		return dateutil.Describe(date, time.Now())
+	},
+	//filterNotes returns the notes which contain one of the words, the case is ignored, f.e. {{filterNotes .Notes "vegan" "vegetarisch"}}
+	"filterNotes": func(notes []string, words ...string) []string {
+		return filterNotes(notes, words, true)
+	},
+	//withoutNotes returns the notes which contain none of the words, the case is ignored
+	"withoutNotes": func(notes []string, words ...string) []string {
+		return filterNotes(notes, words, false)
+	},
+	//hasNote reports whether one of the notes contains the word, the case is ignored
+	"hasNote": func(notes []string, word string) bool {
+		return len(filterNotes(notes, []string{word}, true)) > 0
+	},
+	//veggie reports whether the notes mark a meal as vegetarian or vegan
+	"veggie": func(notes []string) bool {
+		for _, note := range notes {
+			if veggieRegex.MatchString(note) {
+				return true
+			}
+		}
+		return false
+	},
+	//truncate shortens text to width terminal columns and ends it with … when it was longer, f.e. {{truncate 20 .Name}}
+	"truncate": truncate,
+	//join joins a list of texts with the separator, f.e. {{join .Notes ", "}}
+	"join": strings.Join,
+	//add adds two numbers, f.e. {{add $i 1}} to count from 1
+	"add": func(a int, b int) int {
+		return a + b
+	},
+}
+
+//TemplateRenderer writes the data of the commands with a text/template, see TemplateData for the data and templateFuncs for the functions
+type TemplateRenderer struct {
+	template *template.Template
+	options  RenderOptions
+}
+
+//NewTemplateRenderer parses the template text and returns a renderer using it, the name of a built-in template like tmux uses this template
+func NewTemplateRenderer(text string, options RenderOptions) (*TemplateRenderer, error) {
+	if builtin, ok := builtinTemplates[strings.TrimSpace(text)]; ok {
+		text = builtin
+	}
+
+	parsed, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	return &TemplateRenderer{template: parsed, options: options}, nil
+}
+
+//BuiltinTemplates returns the sorted names of the built-in templates
+func BuiltinTemplates() []string {
+	names := make([]string, 0, len(builtinTemplates))
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//RenderCanteen executes the template with Canteen set
+func (r *TemplateRenderer) RenderCanteen(w io.Writer, canteen Canteen) error {
+	return r.execute(w, TemplateData{Canteen: canteen})
+}
+
+//RenderCanteens executes the template with Canteens set
+func (r *TemplateRenderer) RenderCanteens(w io.Writer, canteens []Canteen) error {
+	return r.execute(w, TemplateData{Canteens: canteens})
+}
+
+//RenderCanteenDistances executes the template with Distances set
+func (r *TemplateRenderer) RenderCanteenDistances(w io.Writer, canteens []CanteenDistance) error {
+	return r.execute(w, TemplateData{Distances: canteens})
+}
+
+//RenderCanteenMatches executes the template with Matches set
+func (r *TemplateRenderer) RenderCanteenMatches(w io.Writer, matches []CanteenMatch) error {
+	return r.execute(w, TemplateData{Matches: matches})
+}
+
+//RenderCities executes the template with Cities set
+func (r *TemplateRenderer) RenderCities(w io.Writer, cities []CityCount) error {
+	return r.execute(w, TemplateData{Cities: cities})
+}
+
+//RenderMeals executes the template with Canteen, Day and Days set
+func (r *TemplateRenderer) RenderMeals(w io.Writer, canteen Canteen, day MealsOfDay) error {
+	days := []MealsOfDay{day}
+	return r.execute(w, TemplateData{Canteen: canteen, Day: &days[0], Days: days})
+}
+
+//RenderWeek executes the template with Canteen and Days set
+func (r *TemplateRenderer) RenderWeek(w io.Writer, canteen Canteen, days []MealsOfDay) error {
+	return r.execute(w, TemplateData{Canteen: canteen, Days: days})
+}
+
+//RenderDates executes the template with Canteen and Dates set
+func (r *TemplateRenderer) RenderDates(w io.Writer, canteen Canteen, dates []CanteenDate) error {
+	return r.execute(w, TemplateData{Canteen: canteen, Dates: dates})
+}
+
+func (r *TemplateRenderer) execute(w io.Writer, data TemplateData) error {
+	data.Options = r.options
+	if err := r.template.Execute(w, data); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	return nil
+}
+
+//filterNotes returns the notes which contain one of the words when keep is set, otherwise the notes which contain none of them
+func filterNotes(notes []string, words []string, keep bool) []string {
+	filtered := []string{}
+	for _, note := range notes {
+		contained := false
+		for _, word := range words {
+			if strings.Contains(strings.ToLower(note), strings.ToLower(word)) {
+				contained = true
+				break
+			}
+		}
+		if contained == keep {
+			filtered = append(filtered, note)
+		}
+	}
+	return filtered
+}
+
+//truncate shortens text to width terminal columns and ends it with … when it was longer
+func truncate(width int, text string) string {
+	if displayWidth(text) <= width {
+		return text
+	}
+	if width < 1 {
+		return ""
+	}
+	if width == 1 {
+		return "…"
+	}
+	head, _ := splitAtWidth(text, width-1)
+	return strings.TrimRight(head, " ") + "…"
+}
@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"errors"
+	"gomensa/requests"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMealsOfWeekBulk(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+
+	dates, meals, err := client.RequestCanteenMealsOfWeek(1)
+	if err != nil {
+		t.Fatal("Could not request the meals of the week!", err)
+	}
+	if len(dates) != len(fakeDays) || len(meals) != len(fakeDays) {
+		t.Fatalf("Expected %d days, got %d dates and %d meal lists", len(fakeDays), len(dates), len(meals))
+	}
+	if len(meals[0]) != len(fakeMeals) || dates[2].Closed == false || len(meals[2]) != 0 {
+		t.Errorf("Expected the meals of open days and no meals of closed days, got %v", meals)
+	}
+	if fake.requestCount() != 1 {
+		t.Errorf("Expected the whole week in 1 request, got %d requests", fake.requestCount())
+	}
+
+	dates, _, err = client.RequestCanteenMealsForRange(1, "2020-01-07", "2020-01-09")
+	if err != nil || len(dates) != 3 || dates[0].Date != "2020-01-07" {
+		t.Errorf("Expected the 3 days from 2020-01-07, got %v: %v", dates, err)
+	}
+}
+
+//withoutBulkMeals wraps the fake API like a mirror which does not implement /canteens/{id}/meals
+//the meals of the dates in failingDates fail with a server error
+func withoutBulkMeals(t *testing.T, failingDates ...string) *httptest.Server {
+	fake := newFakeOpenMensa(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/meals") && strings.Contains(r.URL.Path, "/days/") == false {
+			notFound(w)
+			return
+		}
+		for _, date := range failingDates {
+			if strings.Contains(r.URL.Path, "/days/"+date+"/meals") {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+		fake.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMealsOfWeekFallback(t *testing.T) {
+	client := requests.NewClient(withoutBulkMeals(t).URL)
+
+	dates, meals, err := client.RequestCanteenMealsForRange(1, "2020-01-06", "2020-01-07")
+	if err != nil {
+		t.Fatal("Could not request the meals without the bulk endpoint!", err)
+	}
+	if len(dates) != 2 || len(meals[0]) != len(fakeMeals) || len(meals[1]) != len(fakeMeals) {
+		t.Errorf("Expected the meals of 2 days, got %v", meals)
+	}
+}
+
+func TestMealsOfWeekRateLimitedWithoutFallback(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/canteens/1/meals" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	client := requests.NewClient(server.URL)
+	client.Retry.MaxAttempts = 1
+
+	_, _, err := client.RequestCanteenMealsOfWeek(1)
+	if errors.Is(err, requests.ErrRateLimited) == false {
+		t.Errorf("Expected the rate limit of the bulk request, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(paths) != 1 {
+		t.Errorf("Expected no requests for every day after the rate limit, got %v", paths)
+	}
+}
+
+func TestMealsOfWeekPartialFailure(t *testing.T) {
+	client := requests.NewClient(withoutBulkMeals(t, "2020-01-07", "2020-01-09").URL)
+	client.Retry.MaxAttempts = 1
+
+	dates, meals, err := client.RequestCanteenMealsForRange(1, "2020-01-06", "2020-01-10")
+
+	var mealsErr *requests.MealsError
+	if errors.As(err, &mealsErr) == false {
+		t.Fatalf("Expected a MealsError, got %v", err)
+	}
+	if len(mealsErr.Days) != 2 || mealsErr.Days[0].Date != "2020-01-07" || mealsErr.Days[1].Date != "2020-01-09" {
+		t.Errorf("Expected the failed days 2020-01-07 and 2020-01-09, got %v", mealsErr.Days)
+	}
+	if errors.Is(err, requests.ErrServer) == false || errors.Is(mealsErr.ErrorOf("2020-01-09"), requests.ErrServer) == false {
+		t.Errorf("Expected the server error of the failed days, got %v", err)
+	}
+
+	//the days after a failed day are still requested
+	if len(dates) != 5 || meals[1] != nil || meals[3] != nil || len(meals[4]) != len(fakeMeals) {
+		t.Errorf("Expected the meals of all other days, got %v", meals)
+	}
+}
+
+func TestMealsOfDatesParallel(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		running  int
+		maxUsage int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case len(parts) == 3 && parts[2] == "meals":
+			notFound(w)
+		case len(parts) == 3 && parts[2] == "days":
+			writeJSON(w, pageDays(r.URL.Query()))
+		case len(parts) == 5:
+			mutex.Lock()
+			running++
+			if running > maxUsage {
+				maxUsage = running
+			}
+			mutex.Unlock()
+
+			//the earlier days answer later, so the answers arrive in the wrong order
+			day, _ := strconv.Atoi(parts[3][len("2020-01-"):])
+			time.Sleep(time.Duration(20-day) * 3 * time.Millisecond)
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			writeJSON(w, []map[string]interface{}{{"id": 1, "name": parts[3]}})
+		default:
+			notFound(w)
+		}
+	}))
+	defer server.Close()
+	client := requests.NewClient(server.URL)
+	client.Parallelism = 3
+
+	dates, meals, err := client.RequestCanteenMealsOfWeek(1)
+	if err != nil {
+		t.Fatal("Could not request the meals of the week!", err)
+	}
+	for i, date := range dates {
+		if date.Closed {
+			continue
+		}
+		if len(meals[i]) != 1 || meals[i][0].Name != date.Date {
+			t.Errorf("Expected the meals of %s at position %d, got %v", date.Date, i, meals[i])
+		}
+	}
+	if maxUsage > 3 || maxUsage < 2 {
+		t.Errorf("Expected at most 3 and more than 1 concurrent requests, got %d", maxUsage)
+	}
+}
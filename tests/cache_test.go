@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"errors"
+	"gomensa/requests"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCacheReusesResponses(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+	client.Cache = requests.NewCache(t.TempDir())
+
+	if _, _, err := client.RequestCanteenMealOfToday(1); err != nil {
+		t.Fatal("Could not request the meals of today!", err)
+	}
+	count := fake.requestCount()
+
+	_, meals, err := client.RequestCanteenMealOfToday(1)
+	if err != nil || len(meals) != 2 {
+		t.Fatal("Could not request the cached meals of today!", err)
+	}
+	if fake.requestCount() != count {
+		t.Errorf("Cached responses were requested again, %d instead of %d requests", fake.requestCount(), count)
+	}
+
+	//the canteen list keeps its X-Total-Pages header in the cache
+	client.RequestListOfAllCanteens()
+	count = fake.requestCount()
+	canteens, err := client.RequestListOfAllCanteens()
+	if err != nil || len(canteens) != len(fakeCanteens) {
+		t.Errorf("Expected %d cached canteens, got %d: %v", len(fakeCanteens), len(canteens), err)
+	}
+	if fake.requestCount() != count {
+		t.Error("The cached canteen list was requested again")
+	}
+}
+
+func TestCacheOfflineAndRefresh(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+	client.Cache = requests.NewCache(t.TempDir())
+
+	client.Cache.Offline = true
+	_, err := client.RequestCanteenByID(2)
+	if errors.Is(err, requests.ErrNotCached) == false {
+		t.Errorf("Expected ErrNotCached for an uncached canteen in offline mode, got %v", err)
+	}
+	if fake.requestCount() != 0 {
+		t.Error("The offline mode made a request")
+	}
+
+	client.Cache.Offline = false
+	client.Cache.Refresh = true
+	client.RequestCanteenByID(2)
+	client.RequestCanteenByID(2)
+	if fake.requestCount() != 2 {
+		t.Errorf("Expected the refresh mode to always make a request, got %d requests", fake.requestCount())
+	}
+
+	client.Cache.Refresh = false
+	client.Cache.Offline = true
+	canteen, err := client.RequestCanteenByID(2)
+	if err != nil || canteen.ID != 2 {
+		t.Errorf("Expected the cached canteen in offline mode, got %v", err)
+	}
+}
+
+func TestCacheRevalidation(t *testing.T) {
+	var full, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//the canteen list is revalidated with its ETag, the single canteen with its modification date
+		if r.URL.Path == "/canteens" && r.Header.Get("If-None-Match") == `"v1"` ||
+			r.URL.Path == "/canteens/1" && r.Header.Get("If-Modified-Since") == "Mon, 06 Jan 2020 10:00:00 GMT" {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		full++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/canteens" {
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("X-Total-Pages", "1")
+			w.Write([]byte(`[{"id":1,"name":"Mensa"},{"id":2,"name":"Cafeteria"}]`))
+			return
+		}
+		w.Header().Set("Last-Modified", "Mon, 06 Jan 2020 10:00:00 GMT")
+		w.Write([]byte(`{"id":1,"name":"Mensa"}`))
+	}))
+	defer server.Close()
+
+	client := requests.NewClient(server.URL)
+	client.Cache = requests.NewCache(t.TempDir())
+	//every cached response is expired immediately and has to be revalidated
+	client.Cache.CanteenTTL = 0
+
+	for i := 0; i < 3; i++ {
+		canteens, err := client.RequestListOfAllCanteens()
+		if err != nil || len(canteens) != 2 {
+			t.Fatalf("Expected 2 canteens, got %d: %v", len(canteens), err)
+		}
+		canteen, err := client.RequestCanteenByID(1)
+		if err != nil || canteen.Name != "Mensa" {
+			t.Fatal("Could not request the canteen!", err)
+		}
+	}
+
+	if full != 2 || notModified != 4 {
+		t.Errorf("Expected 2 full and 4 conditional requests, got %d full and %d conditional requests", full, notModified)
+	}
+}
+
+func TestCacheStaleOnFailedRevalidation(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"name":"Mensa"}`))
+	}))
+
+	client := requests.NewClient(server.URL)
+	client.Retry.MaxAttempts = 1
+	client.Cache = requests.NewCache(t.TempDir())
+	client.Cache.CanteenTTL = 0
+
+	if _, err := client.RequestCanteenByID(1); err != nil {
+		t.Fatal("Could not request the canteen!", err)
+	}
+
+	//a server error keeps the expired response
+	status = http.StatusServiceUnavailable
+	if canteen, err := client.RequestCanteenByID(1); err != nil || canteen.Name != "Mensa" {
+		t.Errorf("Expected the expired canteen on a server error, got %v: %v", canteen, err)
+	}
+
+	//an answer which is not temporary is returned
+	status = http.StatusNotFound
+	if _, err := client.RequestCanteenByID(1); errors.Is(err, requests.ErrCanteenNotFound) == false {
+		t.Errorf("Expected ErrCanteenNotFound, got %v", err)
+	}
+
+	//a network error keeps the expired response
+	server.Close()
+	if canteen, err := client.RequestCanteenByID(1); err != nil || canteen.Name != "Mensa" {
+		t.Errorf("Expected the expired canteen without a connection, got %v: %v", canteen, err)
+	}
+}
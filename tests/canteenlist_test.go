@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"errors"
+	"gomensa/requests"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func TestListOfAllCanteensOrder(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+	client.Parallelism = 2
+
+	//repeated and concurrent calls must neither deadlock nor mix up the pages
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			canteens, err := client.RequestListOfAllCanteens()
+			if err != nil {
+				t.Error("Could not request the list of all canteens!", err)
+				return
+			}
+			if len(canteens) != len(fakeCanteens) {
+				t.Errorf("Expected %d canteens, got %d", len(fakeCanteens), len(canteens))
+				return
+			}
+			for j, canteen := range canteens {
+				if canteen.ID != j+1 {
+					t.Errorf("Canteens are not in page order, got ID %d at position %d", canteen.ID, j)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestListOfAllCanteensPageError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Pages", "6")
+		if r.URL.Query().Get("page") == "4" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[{"id":1,"name":"Mensa"}]`))
+	}))
+	defer server.Close()
+	client := requests.NewClient(server.URL)
+	client.Retry.MaxAttempts = 1
+
+	for i := 0; i < 3; i++ {
+		canteens, err := client.RequestListOfAllCanteens()
+		if errors.Is(err, requests.ErrServer) == false || canteens != nil {
+			t.Errorf("Expected the error of the failing page, got %v", err)
+		}
+	}
+}
+
+func TestRequestCanteensFilter(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":31,"name":"Mensa"},{"id":63,"name":"Cafeteria"}]`))
+	}))
+	defer server.Close()
+	client := requests.NewClient(server.URL)
+
+	hasCoordinates := true
+	canteens, err := client.RequestCanteens(requests.CanteenListOptions{
+		IDs:            []int{31, 63},
+		Near:           &requests.Coordinates{Latitude: 51.3397, Longitude: 12.3731},
+		NearDistance:   2.5,
+		HasCoordinates: &hasCoordinates,
+	})
+	if err != nil {
+		t.Fatal("Could not request the filtered canteens!", err)
+	}
+	if len(canteens) != 2 {
+		t.Errorf("Expected 2 canteens, got %d", len(canteens))
+	}
+
+	expected := map[string]string{
+		"ids":            "31,63",
+		"near[lat]":      "51.3397",
+		"near[lng]":      "12.3731",
+		"near[dist]":     "2.5",
+		"hasCoordinates": "true",
+		"page":           "1",
+	}
+	for key, value := range expected {
+		if query.Get(key) != value {
+			t.Errorf("Expected query parameter %s=%s, got %q", key, value, query.Get(key))
+		}
+	}
+
+	//a canteen without coordinates is never near a location, so nothing is requested
+	query = nil
+	withoutCoordinates := false
+	_, err = client.RequestCanteens(requests.CanteenListOptions{
+		Near:           &requests.Coordinates{Latitude: 51.3397, Longitude: 12.3731},
+		HasCoordinates: &withoutCoordinates,
+	})
+	if errors.Is(err, requests.ErrNearWithoutCoordinates) == false || query != nil {
+		t.Errorf("Expected ErrNearWithoutCoordinates without a request, got %v and the query %v", err, query)
+	}
+
+	//the zero value requests all canteens without any filter
+	if _, err := client.RequestCanteens(requests.CanteenListOptions{}); err != nil {
+		t.Fatal("Could not request the canteens!", err)
+	}
+	if len(query) != 1 || query.Get("page") != "1" {
+		t.Errorf("Expected only the page parameter, got %v", query)
+	}
+}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"gomensa/requests"
+	"testing"
+)
+
+var cityCanteens = []requests.Canteen{
+	{ID: 1, Name: "Mensa am Park", City: "Leipzig"},
+	{ID: 2, Name: "Mensa Süd", City: "München"},
+	{ID: 3, Name: "Mensa Academica", City: "leipzig"},
+	{ID: 4, Name: "Mensa ohne Stadt", City: ""},
+	{ID: 5, Name: "Mensa Garching", City: "Muenchen"},
+	{ID: 6, Name: "Cafeteria", City: "Berlin"},
+}
+
+func TestCities(t *testing.T) {
+	cities := requests.Cities(cityCanteens)
+	expected := []requests.CityCount{
+		{City: "Berlin", Count: 1},
+		{City: "Leipzig", Count: 2},
+		{City: "München", Count: 2},
+	}
+
+	if len(cities) != len(expected) {
+		t.Fatalf("Expected %d cities, got %v", len(expected), cities)
+	}
+	for i, city := range cities {
+		if city != expected[i] {
+			t.Errorf("Expected %v at position %d, got %v", expected[i], i, city)
+		}
+	}
+}
+
+func TestCanteensInCity(t *testing.T) {
+	tests := []struct {
+		city     string
+		expected []int
+	}{
+		{"Leipzig", []int{1, 3}},
+		{"LEIPZIG ", []int{1, 3}},
+		{"münchen", []int{2, 5}},
+		{"munchen", []int{2}},
+		{"Dresden", []int{}},
+		{"", []int{}},
+	}
+
+	for _, test := range tests {
+		canteens := requests.CanteensInCity(cityCanteens, test.city)
+		if len(canteens) != len(test.expected) {
+			t.Errorf("Expected %d mensas in %q, got %d", len(test.expected), test.city, len(canteens))
+			continue
+		}
+		for i, canteen := range canteens {
+			if canteen.ID != test.expected[i] {
+				t.Errorf("Expected mensa %d at position %d in %q, got %d", test.expected[i], i, test.city, canteen.ID)
+			}
+		}
+	}
+}
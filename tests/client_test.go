@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"gomensa/requests"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientBaseURL(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+	client.UserAgent = "gomensa-test"
+
+	canteen, err := client.RequestCanteenByID(3)
+	if err != nil {
+		t.Fatal("Could not retrieve single canteen from the fake API!", err)
+	}
+	if canteen.ID != 3 || canteen.City != "Berlin" {
+		t.Errorf("Retrieved the wrong canteen: %v", *canteen)
+	}
+
+	for _, userAgent := range fake.userAgents {
+		if userAgent != "gomensa-test" {
+			t.Errorf("Client did not send its User-Agent, got %q", userAgent)
+		}
+	}
+}
+
+func TestClientMeals(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+
+	date, meals, err := client.RequestCanteenMealOfToday(1)
+	if err != nil {
+		t.Fatal("Could not retrieve the meals of today from the fake API!", err)
+	}
+	if date.Date != "2020-01-06" {
+		t.Errorf("Expected the first day of the fake API, got %q", date.Date)
+	}
+	if len(meals) != 2 {
+		t.Errorf("Expected 2 meals, got %d", len(meals))
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+
+	_, err := client.RequestCanteenByID(6666)
+	if errors.Is(err, requests.ErrCanteenNotFound) == false {
+		t.Errorf("Expected ErrCanteenNotFound for an unknown canteen, got %v", err)
+	}
+
+	_, err = client.RequestCanteenWeek(6666)
+	if errors.Is(err, requests.ErrCanteenNotFound) == false {
+		t.Errorf("Expected ErrCanteenNotFound for the days of an unknown canteen, got %v", err)
+	}
+
+	_, err = client.RequestCanteenDate(1, "2020-02-01")
+	if errors.Is(err, requests.ErrNoDataForDate) == false {
+		t.Errorf("Expected ErrNoDataForDate for a date without data, got %v", err)
+	}
+
+	_, err = client.RequestCanteenDate(1, "tomorrow")
+	if errors.Is(err, requests.ErrInvalidDate) == false {
+		t.Errorf("Expected ErrInvalidDate for a malformed date, got %v", err)
+	}
+}
+
+func TestClientStatusCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/canteens/1":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"database unavailable"}`))
+		case "/canteens/2":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>Please log in to the Wi-Fi</html>"))
+		case "/canteens/3":
+			w.Write([]byte("this is not json"))
+		}
+	}))
+	defer server.Close()
+	client := requests.NewClient(server.URL)
+	client.Retry.MaxAttempts = 1
+
+	_, err := client.RequestCanteenByID(1)
+	var apiErr *requests.APIError
+	if errors.Is(err, requests.ErrServer) == false || errors.As(err, &apiErr) == false {
+		t.Fatalf("Expected an APIError wrapping ErrServer, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusInternalServerError || apiErr.Message != "database unavailable" {
+		t.Errorf("APIError does not carry the status and the decoded message: %+v", apiErr)
+	}
+
+	_, err = client.RequestCanteenByID(2)
+	if errors.Is(err, requests.ErrInvalidResponse) == false {
+		t.Errorf("Expected ErrInvalidResponse for a HTML response, got %v", err)
+	}
+
+	_, err = client.RequestCanteenByID(3)
+	if errors.Is(err, requests.ErrInvalidResponse) == false {
+		t.Errorf("Expected ErrInvalidResponse for a non-JSON body, got %v", err)
+	}
+}
+
+func TestClientContextCancel(t *testing.T) {
+	//the server hangs until the client gives up
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	client := requests.NewClient(server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.RequestCanteenByIDContext(ctx, 1)
+	if errors.Is(err, context.DeadlineExceeded) == false {
+		t.Errorf("Expected the request to be aborted by the context, got %v", err)
+	}
+
+	_, err = client.RequestListOfAllCanteensContext(ctx)
+	if errors.Is(err, context.DeadlineExceeded) == false {
+		t.Errorf("Expected the canteen list request to be aborted by the context, got %v", err)
+	}
+}
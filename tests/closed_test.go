@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"errors"
+	"gomensa/requests"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMealsOfClosedDay(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+
+	tests := map[string]string{
+		"2020-01-08": "2020-01-09",
+		//the fake API knows no open day after the closed weekend
+		"2020-01-11": "",
+	}
+	for date, nextOpenDate := range tests {
+		canteenDate, meals, err := client.RequestCanteenMealsOfDate(1, date)
+
+		var closedErr *requests.ClosedError
+		if errors.As(err, &closedErr) == false || errors.Is(err, requests.ErrCanteenClosed) == false {
+			t.Errorf("Expected a ClosedError for %s, got %v", date, err)
+			continue
+		}
+		if closedErr.Date != date || closedErr.NextOpenDate != nextOpenDate {
+			t.Errorf("Expected the next open day %q after %s, got %q", nextOpenDate, date, closedErr.NextOpenDate)
+		}
+		if canteenDate == nil || canteenDate.Closed == false || meals != nil {
+			t.Errorf("Expected the closed date without meals for %s, got %v and %v", date, canteenDate, meals)
+		}
+	}
+}
+
+func TestMealsOfWeekSkipsClosedDays(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	var (
+		mutex sync.Mutex
+		paths []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/1/meals") {
+			notFound(w)
+			return
+		}
+		mutex.Lock()
+		paths = append(paths, r.URL.Path)
+		mutex.Unlock()
+		fake.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	client := requests.NewClient(server.URL)
+
+	dates, meals, err := client.RequestCanteenMealsOfWeek(1)
+	if err != nil {
+		t.Fatal("Could not request the meals of the week!", err)
+	}
+	for i, date := range dates {
+		if date.Closed && (meals[i] == nil || len(meals[i]) != 0) {
+			t.Errorf("Expected no meals on the closed day %s, got %v", date.Date, meals[i])
+		}
+	}
+	for _, path := range paths {
+		if strings.Contains(path, "2020-01-08") || strings.Contains(path, "2020-01-11") || strings.Contains(path, "2020-01-12") {
+			t.Errorf("Expected no request for the meals of a closed day, got %s", path)
+		}
+	}
+}
+
+func TestNextOpenDay(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+
+	tests := map[string]string{
+		"2020-01-06": "2020-01-06",
+		"2020-01-08": "2020-01-09",
+		"2020-01-05": "2020-01-06",
+	}
+	for from, expected := range tests {
+		date, meals, err := client.RequestNextOpenDay(1, from)
+		if err != nil {
+			t.Errorf("Could not request the next open day from %s: %v", from, err)
+			continue
+		}
+		if date.Date != expected || date.Closed || len(meals) != len(fakeMeals) {
+			t.Errorf("Expected the open day %s from %s, got %v with %d meals", expected, from, date, len(meals))
+		}
+	}
+
+	//only closed days follow
+	if _, _, err := client.RequestNextOpenDay(1, "2020-01-11"); errors.Is(err, requests.ErrNoDataForDate) == false {
+		t.Errorf("Expected ErrNoDataForDate without an open day, got %v", err)
+	}
+}
+
+func TestNextOpenDayPaging(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//the first 60 days are closed, the first open day is 2020-03-01
+		if strings.HasSuffix(r.URL.Path, "/days") {
+			query := r.URL.Query()
+			pages = append(pages, query.Get("page"))
+			page, _ := strconv.Atoi(query.Get("page"))
+			limit, _ := strconv.Atoi(query.Get("limit"))
+
+			start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			days := []requests.CanteenDate{}
+			for i := (page - 1) * limit; i < page*limit && i < 90; i++ {
+				days = append(days, requests.CanteenDate{Date: start.AddDate(0, 0, i).Format("2006-01-02"), Closed: i < 60})
+			}
+			writeJSON(w, days)
+			return
+		}
+		writeJSON(w, fakeMeals)
+	}))
+	defer server.Close()
+	client := requests.NewClient(server.URL)
+
+	date, _, err := client.RequestNextOpenDay(1, "2020-01-01")
+	if err != nil {
+		t.Fatal("Could not request the next open day!", err)
+	}
+	if date.Date != "2020-03-01" || len(pages) != 2 {
+		t.Errorf("Expected 2020-03-01 on the second page, got %s after the pages %v", date.Date, pages)
+	}
+}
+
+func TestNextOpenDayIgnoredPaging(t *testing.T) {
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//a mirror which ignores the page and always sends the same closed days
+		requestCount++
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		days := []requests.CanteenDate{}
+		for i := 0; i < limit; i++ {
+			days = append(days, requests.CanteenDate{Date: start.AddDate(0, 0, i).Format("2006-01-02"), Closed: true})
+		}
+		writeJSON(w, days)
+	}))
+	defer server.Close()
+	client := requests.NewClient(server.URL)
+
+	_, _, err := client.RequestNextOpenDay(1, "2020-01-01")
+	if errors.Is(err, requests.ErrNoDataForDate) == false {
+		t.Errorf("Expected ErrNoDataForDate, got %v", err)
+	}
+	if requestCount != 2 {
+		t.Errorf("Expected to stop after the repeated second page, got %d requests", requestCount)
+	}
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"errors"
+	"gomensa/dateutil"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	//a wednesday
+	now := time.Date(2020, 1, 8, 15, 30, 0, 0, time.Local)
+
+	tests := map[string]string{
+		"today":          "2020-01-08",
+		" Tomorrow ":     "2020-01-09",
+		"heute":          "2020-01-08",
+		"morgen":         "2020-01-09",
+		"übermorgen":     "2020-01-10",
+		"yesterday":      "2020-01-07",
+		"monday":         "2020-01-13",
+		"Montag":         "2020-01-13",
+		"wednesday":      "2020-01-08",
+		"next wednesday": "2020-01-15",
+		"next friday":    "2020-01-10",
+		"nächsten Fr":    "2020-01-10",
+		"sonntag":        "2020-01-12",
+		"+3":             "2020-01-11",
+		"-1":             "2020-01-07",
+		"+30":            "2020-02-07",
+		"in 2 days":      "2020-01-10",
+		"in 1 day":       "2020-01-09",
+		"in 3 Tagen":     "2020-01-11",
+		"2020-02-29":     "2020-02-29",
+		"31.01.2020":     "2020-01-31",
+		"1.2.2020":       "2020-02-01",
+		"24.12.":         "2020-12-24",
+	}
+	for input, expected := range tests {
+		date, err := dateutil.Parse(input, now)
+		if err != nil {
+			t.Errorf("Could not parse %q: %v", input, err)
+			continue
+		}
+		if date.Format(dateutil.Layout) != expected {
+			t.Errorf("Expected %s for %q, got %s", expected, input, date.Format(dateutil.Layout))
+		}
+	}
+
+	invalid := []string{"", "2020-99-99", "2019-02-29", "31.02.2020", "2020-1-1x", "next", "next month", "in days", "someday", "63"}
+	for _, input := range invalid {
+		if _, err := dateutil.Parse(input, now); errors.Is(err, dateutil.ErrInvalidDate) == false {
+			t.Errorf("Expected ErrInvalidDate for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	//a wednesday, monday..friday is the next week and not the past monday until this friday
+	now := time.Date(2020, 1, 8, 15, 30, 0, 0, time.Local)
+
+	tests := map[string][2]string{
+		"monday..friday":         {"2020-01-13", "2020-01-17"},
+		"friday..monday":         {"2020-01-10", "2020-01-13"},
+		"today..friday":          {"2020-01-08", "2020-01-10"},
+		"wednesday..tuesday":     {"2020-01-08", "2020-01-14"},
+		"tomorrow..+3":           {"2020-01-09", "2020-01-12"},
+		"2020-01-27..2020-01-31": {"2020-01-27", "2020-01-31"},
+	}
+	for input, expected := range tests {
+		from, to, err := dateutil.ParseRange(input, now)
+		if err != nil {
+			t.Errorf("Could not parse %q: %v", input, err)
+			continue
+		}
+		if from.Format(dateutil.Layout) != expected[0] || to.Format(dateutil.Layout) != expected[1] {
+			t.Errorf("Expected %s..%s for %q, got %s..%s", expected[0], expected[1], input, from.Format(dateutil.Layout), to.Format(dateutil.Layout))
+		}
+	}
+
+	for _, input := range []string{"monday", "monday..", "monday..friday..sunday", "someday..friday"} {
+		if _, _, err := dateutil.ParseRange(input, now); errors.Is(err, dateutil.ErrInvalidDate) == false {
+			t.Errorf("Expected ErrInvalidDate for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestDescribeDate(t *testing.T) {
+	now := time.Date(2020, 1, 8, 15, 30, 0, 0, time.Local)
+
+	tests := map[string]string{
+		"2020-01-08": "today",
+		"2020-01-09": "tomorrow",
+		"2020-01-10": "on Friday 2020-01-10",
+		"someday":    "on someday",
+	}
+	for date, expected := range tests {
+		if day := dateutil.DescribeDay(date, now); day != expected {
+			t.Errorf("Expected %q for %s, got %q", expected, date, day)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+//fakeCanteens are served by the fake OpenMensa API, two canteens per page
+var fakeCanteens = []map[string]interface{}{
+	{"id": 1, "name": "Mensa Eins", "city": "Leipzig", "address": "Straße 1", "coordinates": []float64{51.3377, 12.3800}},
+	{"id": 2, "name": "Mensa Zwei", "city": "Leipzig", "address": "Straße 2", "coordinates": []float64{51.3270, 12.3900}},
+	{"id": 3, "name": "Mensa Drei", "city": "Berlin", "address": "Straße 3", "coordinates": []float64{52.5100, 13.3260}},
+	{"id": 4, "name": "Mensa Vier", "city": "Dresden", "address": "Straße 4", "coordinates": nil},
+	{"id": 5, "name": "Mensa Fünf", "city": "Halle", "address": "Straße 5", "coordinates": []float64{51.4800, 11.9700}},
+}
+
+//fakeDays are the days of every canteen of the fake OpenMensa API
+var fakeDays = []map[string]interface{}{
+	{"date": "2020-01-06", "closed": false},
+	{"date": "2020-01-07", "closed": false},
+	{"date": "2020-01-08", "closed": true},
+	{"date": "2020-01-09", "closed": false},
+	{"date": "2020-01-10", "closed": false},
+	{"date": "2020-01-11", "closed": true},
+	{"date": "2020-01-12", "closed": true},
+}
+
+//fakeMeals are the meals served on every open day of the fake OpenMensa API
+var fakeMeals = []map[string]interface{}{
+	{"id": 10, "name": "Nudeln mit Tomatensoße", "category": "Pasta", "notes": []string{"vegetarisch"}, "prices": map[string]float64{"students": 2.1, "employees": 3.5, "others": 4.2}},
+	{"id": 11, "name": "Currywurst mit Pommes", "category": "Hauptgericht", "notes": []string{}, "prices": map[string]float64{"students": 2.5, "employees": 4, "others": 5}},
+}
+
+//fakeOpenMensa is a minimal in-memory implementation of the OpenMensa v2 API
+type fakeOpenMensa struct {
+	*httptest.Server
+
+	mutex sync.Mutex
+	//userAgents holds the User-Agent header of every request
+	userAgents []string
+}
+
+//requestCount returns the number of requests the fake API received
+func (f *fakeOpenMensa) requestCount() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.userAgents)
+}
+
+//newFakeOpenMensa starts a fake OpenMensa API which is closed when the test finishes
+func newFakeOpenMensa(t *testing.T) *fakeOpenMensa {
+	fake := &fakeOpenMensa{}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func (f *fakeOpenMensa) serve(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	f.userAgents = append(f.userAgents, r.UserAgent())
+	f.mutex.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) == 0 || parts[0] != "canteens" {
+		notFound(w)
+		return
+	}
+
+	if len(parts) == 1 {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		totalPages := (len(fakeCanteens) + 1) / 2
+		w.Header().Set("X-Total-Pages", strconv.Itoa(totalPages))
+
+		from := (page - 1) * 2
+		to := from + 2
+		if from > len(fakeCanteens) {
+			from = len(fakeCanteens)
+		}
+		if to > len(fakeCanteens) {
+			to = len(fakeCanteens)
+		}
+		writeJSON(w, fakeCanteens[from:to])
+		return
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil || id < 1 || id > len(fakeCanteens) {
+		notFound(w)
+		return
+	}
+
+	switch {
+	case len(parts) == 2:
+		writeJSON(w, fakeCanteens[id-1])
+	case len(parts) == 3 && parts[2] == "days":
+		writeJSON(w, pageDays(r.URL.Query()))
+	case len(parts) == 3 && parts[2] == "meals":
+		days := []map[string]interface{}{}
+		for _, day := range pageDays(r.URL.Query()) {
+			meals := fakeMeals
+			if day["closed"] == true {
+				meals = []map[string]interface{}{}
+			}
+			days = append(days, map[string]interface{}{"date": day["date"], "closed": day["closed"], "meals": meals})
+		}
+		writeJSON(w, days)
+	case len(parts) == 5 && parts[2] == "days" && parts[4] == "meals":
+		for _, day := range fakeDays {
+			if day["date"] == parts[3] {
+				writeJSON(w, fakeMeals)
+				return
+			}
+		}
+		notFound(w)
+	default:
+		notFound(w)
+	}
+}
+
+//pageDays returns the fakeDays starting at the start parameter, limit and page select a page of them like the OpenMensa API
+func pageDays(query url.Values) []map[string]interface{} {
+	days := []map[string]interface{}{}
+	for _, day := range fakeDays {
+		if day["date"].(string) >= query.Get("start") {
+			days = append(days, day)
+		}
+	}
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 {
+		return days
+	}
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	from := (page - 1) * limit
+	if from > len(days) {
+		from = len(days)
+	}
+	to := from + limit
+	if to > len(days) {
+		to = len(days)
+	}
+	return days[from:to]
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func notFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`{"message":"Could not find resource"}`))
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"errors"
+	"gomensa/requests"
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	leipzig := requests.Coordinates{Latitude: 51.3397, Longitude: 12.3731}
+	berlin := requests.Coordinates{Latitude: 52.5200, Longitude: 13.4050}
+
+	if distance := requests.Distance(leipzig, berlin); math.Abs(distance-149.5) > 1 {
+		t.Errorf("Expected a distance of about 149.5 km between Leipzig and Berlin, got %.2f km", distance)
+	}
+	if distance := requests.Distance(leipzig, leipzig); distance != 0 {
+		t.Errorf("Expected no distance between the same location, got %.2f km", distance)
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	coordinates, err := requests.ParseCoordinates("51.3397, 12.3731")
+	if err != nil || coordinates.Latitude != 51.3397 || coordinates.Longitude != 12.3731 {
+		t.Errorf("Could not parse valid coordinates, got %v: %v", coordinates, err)
+	}
+
+	for _, location := range []string{"", "51.3", "a,b", "91,0", "0,181", "1,2,3"} {
+		if _, err := requests.ParseCoordinates(location); errors.Is(err, requests.ErrInvalidCoordinates) == false {
+			t.Errorf("Expected ErrInvalidCoordinates for %q, got %v", location, err)
+		}
+	}
+}
+
+func TestCanteensNear(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+
+	canteens, err := client.RequestListOfAllCanteens()
+	if err != nil {
+		t.Fatal("Could not request the list of all canteens!", err)
+	}
+	if canteens[0].Coordinates == nil || canteens[0].Coordinates.Latitude != 51.3377 {
+		t.Fatalf("The coordinates of the canteen were not parsed: %v", canteens[0].Coordinates)
+	}
+	if canteens[3].Coordinates != nil {
+		t.Errorf("A canteen without coordinates should have nil coordinates, got %v", canteens[3].Coordinates)
+	}
+
+	near := requests.CanteensNear(canteens, requests.Coordinates{Latitude: 51.3300, Longitude: 12.3890}, 50)
+	if len(near) != 3 {
+		t.Fatalf("Expected 3 canteens within 50 km, got %d", len(near))
+	}
+	for i, id := range []int{2, 1, 5} {
+		if near[i].Canteen.ID != id {
+			t.Errorf("Expected canteen %d at position %d, got %d", id, i, near[i].Canteen.ID)
+		}
+	}
+
+	if all := requests.CanteensNear(canteens, requests.Coordinates{}, 0); len(all) != 4 {
+		t.Errorf("Expected all 4 canteens with coordinates without a radius, got %d", len(all))
+	}
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"bytes"
+	"gomensa/requests"
+	"strings"
+	"testing"
+)
+
+//markupMeals returns a new meal with characters which have a meaning in markdown and HTML for every test
+func markupMeals() []requests.CanteenMeal {
+	meals := []requests.CanteenMeal{
+		{Name: "Pasta | Pesto <script>alert(1)</script>", Category: "Pasta & Co", Notes: []string{"vegan", "*scharf*"}},
+	}
+	meals[0].Prices.Students = 2.5
+	return meals
+}
+
+func TestRenderWeekMarkdown(t *testing.T) {
+	canteen := requests.Canteen{ID: 63, Name: "Mensa am Park"}
+	dates := []requests.CanteenDate{{Date: "2020-01-06"}, {Date: "2020-01-07"}, {Date: "2020-01-08", Closed: true}}
+	mealsErr := &requests.MealsError{Days: []requests.DayError{{Date: "2020-01-07", Err: requests.ErrServer}}}
+
+	var output bytes.Buffer
+	if err := newRenderer(t, "markdown", requests.RenderOptions{}).RenderWeek(&output, canteen, requests.NewMealsOfDays(dates, [][]requests.CanteenMeal{markupMeals(), nil, nil}, mealsErr)); err != nil {
+		t.Fatal("Could not render the week!", err)
+	}
+
+	for _, expected := range []string{
+		"# Mensa am Park\n\n## Monday 2020-01-06\n\n",
+		"| # | Category | Meal | Notes | Students | Employees | Others |\n| --: | --- | --- | --- | --: | --: | --: |\n",
+		"| 1 | Pasta & Co | Pasta \\| Pesto &lt;script&gt;alert(1)&lt;/script&gt; | vegan, \\*scharf\\* | 2.50€ | - | - |\n",
+		"## Tuesday 2020-01-07\n\n*Could not retrieve the meals of this day: ",
+		"## Wednesday 2020-01-08\n\n*closed*\n",
+	} {
+		if strings.Contains(output.String(), expected) == false {
+			t.Errorf("Expected %q in the markdown, got %q", expected, output.String())
+		}
+	}
+}
+
+func TestRenderMealsHTML(t *testing.T) {
+	canteen := requests.Canteen{ID: 63, Name: "Mensa <am> Park"}
+	day := requests.NewMealsOfDay(requests.CanteenDate{Date: "2020-01-06"}, markupMeals(), nil)
+
+	var output bytes.Buffer
+	if err := newRenderer(t, "html", requests.RenderOptions{PriceGroup: requests.PriceStudents}).RenderMeals(&output, canteen, day); err != nil {
+		t.Fatal("Could not render the meals!", err)
+	}
+
+	html := output.String()
+	if strings.Contains(html, "<script>") || strings.Contains(html, "<am>") {
+		t.Errorf("Expected the names to be escaped, got %q", html)
+	}
+	for _, expected := range []string{
+		"<h1>Mensa &lt;am&gt; Park</h1>",
+		"<td>Pasta | Pesto &lt;script&gt;alert(1)&lt;/script&gt;</td><td>vegan, *scharf*</td><td class=\"number\">2.50€</td></tr>",
+		"<th class=\"number\">Students</th></tr>",
+	} {
+		if strings.Contains(html, expected) == false {
+			t.Errorf("Expected %q in the HTML, got %q", expected, html)
+		}
+	}
+	if strings.Contains(html, "<!DOCTYPE html>") || strings.Contains(html, "Employees") {
+		t.Errorf("Expected a fragment with only the price for students, got %q", html)
+	}
+
+	output.Reset()
+	closed := requests.NewMealsOfDay(requests.CanteenDate{Date: "2020-01-08", Closed: true}, nil, &requests.ClosedError{Date: "2020-01-08", NextOpenDate: "2020-01-10"})
+	if err := newRenderer(t, "html", requests.RenderOptions{Standalone: true}).RenderMeals(&output, canteen, closed); err != nil {
+		t.Fatal("Could not render the closed day!", err)
+	}
+	page := output.String()
+	for _, expected := range []string{"<!DOCTYPE html>", "<title>Mensa &lt;am&gt; Park</title>", "<style>", "<p class=\"closed\">closed, the next open day is Friday 2020-01-10</p>", "</html>"} {
+		if strings.Contains(page, expected) == false {
+			t.Errorf("Expected %q in the standalone page, got %q", expected, page)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"errors"
+	"gomensa/requests"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMealsOfDate(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+
+	date, meals, err := client.RequestCanteenMealsOfDate(1, "2020-01-09")
+	if err != nil {
+		t.Fatal("Could not request the meals of the date!", err)
+	}
+	if date.Date != "2020-01-09" || len(meals) != len(fakeMeals) {
+		t.Errorf("Expected %d meals of 2020-01-09, got %d meals of %s", len(fakeMeals), len(meals), date.Date)
+	}
+
+	if _, _, err := client.RequestCanteenMealsOfDate(1, "2020-02-01"); errors.Is(err, requests.ErrNoDataForDate) == false {
+		t.Errorf("Expected ErrNoDataForDate for an unknown date, got %v", err)
+	}
+
+	//dates which look right but do not exist are rejected before making a request
+	if _, _, err := client.RequestCanteenMealsOfDate(1, "2020-99-99"); errors.Is(err, requests.ErrInvalidDate) == false {
+		t.Errorf("Expected ErrInvalidDate for 2020-99-99, got %v", err)
+	}
+}
+
+func TestMealsForRange(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+
+	dates, meals, err := client.RequestCanteenMealsForRange(1, "2020-01-06", "2020-01-07")
+	if err != nil {
+		t.Fatal("Could not request the meals of the range!", err)
+	}
+	if len(dates) != 2 || len(meals) != 2 || dates[0].Date != "2020-01-06" || dates[1].Date != "2020-01-07" {
+		t.Errorf("Expected the dates 2020-01-06 and 2020-01-07, got %v", dates)
+	}
+
+	invalid := [][2]string{
+		{"2020-01-07", "2020-01-06"},
+		{"2020-13-01", "2020-01-06"},
+		{"2020-01-06", "tomorrow"},
+	}
+	for _, dateRange := range invalid {
+		_, _, err := client.RequestCanteenMealsForRange(1, dateRange[0], dateRange[1])
+		if errors.Is(err, requests.ErrInvalidDate) == false && errors.Is(err, requests.ErrInvalidDateRange) == false {
+			t.Errorf("Expected an invalid date error for %v, got %v", dateRange, err)
+		}
+	}
+}
+
+func TestDatesForRangePaging(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		pages = append(pages, query.Get("page"))
+		page, _ := strconv.Atoi(query.Get("page"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		if query.Get("start") != "2020-01-01" {
+			t.Errorf("Expected the start 2020-01-01, got %q", query.Get("start"))
+		}
+
+		//the API knows the next 120 days
+		days := []requests.CanteenDate{}
+		for i := (page - 1) * limit; i < page*limit && i < 120; i++ {
+			days = append(days, requests.CanteenDate{Date: start.AddDate(0, 0, i).Format("2006-01-02")})
+		}
+		writeJSON(w, days)
+	}))
+	defer server.Close()
+	client := requests.NewClient(server.URL)
+
+	dates, err := client.RequestCanteenDatesForRange(1, "2020-01-01", "2020-03-15")
+	if err != nil {
+		t.Fatal("Could not request the dates of the range!", err)
+	}
+	if len(dates) != 75 || dates[0].Date != "2020-01-01" || dates[74].Date != "2020-03-15" {
+		t.Errorf("Expected the 75 days from 2020-01-01 to 2020-03-15, got %d days", len(dates))
+	}
+	if len(pages) != 2 {
+		t.Errorf("Expected 2 requested pages, got %v", pages)
+	}
+}
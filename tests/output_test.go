@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"gomensa/requests"
+	"strings"
+	"testing"
+)
+
+var outputDates = []requests.CanteenDate{
+	{Date: "2020-01-06"},
+	{Date: "2020-01-07"},
+	{Date: "2020-01-08", Closed: true},
+}
+
+var outputMeals = [][]requests.CanteenMeal{
+	{{ID: 10, Name: "Nudeln, mit \"Soße\"", Category: "Pasta", Notes: []string{"vegetarisch", "Gluten"}}},
+	nil,
+	{},
+}
+
+func TestNewMealsOfDays(t *testing.T) {
+	mealsErr := &requests.MealsError{Days: []requests.DayError{{Date: "2020-01-07", Err: requests.ErrServer}}}
+	days := requests.NewMealsOfDays(outputDates, outputMeals, mealsErr)
+
+	if len(days) != 3 || len(days[0].Meals) != 1 || days[0].Error != "" {
+		t.Fatalf("Expected the meals of the first day, got %v", days)
+	}
+	if days[1].Meals == nil || days[1].Error != requests.ErrServer.Error() {
+		t.Errorf("Expected the error of the failed day, got %v", days[1])
+	}
+	if days[2].Closed == false {
+		t.Errorf("Expected the closed day, got %v", days[2])
+	}
+
+	day := requests.NewMealsOfDay(outputDates[2], nil, &requests.ClosedError{Date: "2020-01-08", NextOpenDate: "2020-01-09"})
+	if day.NextOpenDate != "2020-01-09" || day.Meals == nil {
+		t.Errorf("Expected the next open day of the closed day, got %v", day)
+	}
+}
+
+//newRenderer returns the renderer of the format or stops the test
+func newRenderer(t *testing.T, format string, options requests.RenderOptions) requests.Renderer {
+	renderer, err := requests.NewRenderer(format, options)
+	if err != nil {
+		t.Fatalf("Could not create the %s renderer: %v", format, err)
+	}
+	return renderer
+}
+
+func TestRenderWeekStructured(t *testing.T) {
+	days := requests.NewMealsOfDays(outputDates, outputMeals, nil)
+	canteen := requests.Canteen{ID: 63, Name: "Mensa am Park"}
+
+	var jsonOutput bytes.Buffer
+	if err := newRenderer(t, "json", requests.RenderOptions{}).RenderWeek(&jsonOutput, canteen, days); err != nil {
+		t.Fatal("Could not write JSON!", err)
+	}
+	var decoded []struct {
+		Canteen requests.Canteen `json:"canteen"`
+		requests.MealsOfDay
+	}
+	if err := json.Unmarshal(jsonOutput.Bytes(), &decoded); err != nil || len(decoded) != 3 || decoded[0].Meals[0].Notes[1] != "Gluten" || decoded[2].Canteen.ID != 63 {
+		t.Errorf("Expected the days as JSON array, got %s: %v", jsonOutput.String(), err)
+	}
+
+	var jsonlOutput bytes.Buffer
+	if err := newRenderer(t, "jsonl", requests.RenderOptions{}).RenderWeek(&jsonlOutput, canteen, days); err != nil {
+		t.Fatal("Could not write JSON Lines!", err)
+	}
+	lines := strings.Split(strings.TrimSpace(jsonlOutput.String()), "\n")
+	if len(lines) != 3 {
+		t.Errorf("Expected a line for every day, got %q", jsonlOutput.String())
+	}
+	for _, line := range lines {
+		var day requests.MealsOfDay
+		if err := json.Unmarshal([]byte(line), &day); err != nil {
+			t.Errorf("Expected a JSON object per line, got %q: %v", line, err)
+		}
+	}
+
+	var csvOutput bytes.Buffer
+	if err := newRenderer(t, "csv", requests.RenderOptions{}).RenderWeek(&csvOutput, canteen, days); err != nil {
+		t.Fatal("Could not write CSV!", err)
+	}
+	records, err := csv.NewReader(&csvOutput).ReadAll()
+	if err != nil {
+		t.Fatal("Could not read the written CSV!", err)
+	}
+	//the header, one meal and two days without meals
+	if len(records) != 4 || records[1][0] != "63" || records[1][1] != "Mensa am Park" || records[1][6] != "Nudeln, mit \"Soße\"" || records[1][8] != "vegetarisch; Gluten" || records[3][3] != "true" {
+		t.Errorf("Expected a line for every meal and every day without meals, got %v", records)
+	}
+}
+
+func TestRenderDatesStructured(t *testing.T) {
+	canteen := requests.Canteen{ID: 63, Name: "Mensa am Park"}
+
+	var output bytes.Buffer
+	if err := newRenderer(t, "jsonl", requests.RenderOptions{}).RenderDates(&output, canteen, outputDates[1:]); err != nil {
+		t.Fatal("Could not write JSON Lines!", err)
+	}
+	expected := `{"canteen":{"id":63,"name":"Mensa am Park","city":"","address":""},"date":"2020-01-07","closed":false}` + "\n" +
+		`{"canteen":{"id":63,"name":"Mensa am Park","city":"","address":""},"date":"2020-01-08","closed":true}` + "\n"
+	if output.String() != expected {
+		t.Errorf("Expected the canteen in every line, got %q", output.String())
+	}
+
+	output.Reset()
+	closed := requests.NewMealsOfDay(outputDates[2], nil, &requests.ClosedError{Date: "2020-01-08", NextOpenDate: "2020-01-09"})
+	if err := newRenderer(t, "csv", requests.RenderOptions{}).RenderMeals(&output, canteen, closed); err != nil {
+		t.Fatal("Could not write CSV!", err)
+	}
+	expected = "canteen_id,canteen_name,date,closed,next_open_date,id,name,category,notes,price_students,price_employees,price_pupils,price_others,error\n" +
+		"63,Mensa am Park,2020-01-08,true,2020-01-09,,,,,,,,,\n"
+	if output.String() != expected {
+		t.Errorf("Expected the canteen and the next open day, got %q", output.String())
+	}
+}
+
+func TestRenderCanteenStructured(t *testing.T) {
+	canteen := requests.Canteen{ID: 63, Name: "Mensa am Park", City: "Leipzig", Coordinates: &requests.Coordinates{Latitude: 51.3, Longitude: 12.4}}
+
+	var output bytes.Buffer
+	if err := newRenderer(t, "json", requests.RenderOptions{}).RenderCanteen(&output, canteen); err != nil {
+		t.Fatal("Could not write JSON!", err)
+	}
+	var decoded requests.Canteen
+	if err := json.Unmarshal(output.Bytes(), &decoded); err != nil || decoded.ID != 63 || decoded.Coordinates.Longitude != 12.4 {
+		t.Errorf("Expected the canteen as JSON object, got %s: %v", output.String(), err)
+	}
+
+	output.Reset()
+	if err := newRenderer(t, "csv", requests.RenderOptions{}).RenderCanteens(&output, []requests.Canteen{canteen}); err != nil {
+		t.Fatal("Could not write CSV!", err)
+	}
+	if output.String() != "id,name,city,address,latitude,longitude\n63,Mensa am Park,Leipzig,,51.3,12.4\n" {
+		t.Errorf("Unexpected CSV %q", output.String())
+	}
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"gomensa/requests"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterConcurrentClients(t *testing.T) {
+	fake := newFakeOpenMensa(t)
+	client := requests.NewClient(fake.URL)
+	//the first request is allowed right away, every further one after 20ms
+	client.RateLimiter = requests.NewRateLimiter(50, 1)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.RequestCanteenByID(1); err != nil {
+				t.Error("Could not request the canteen!", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("6 requests with 50 requests per second took only %s", elapsed)
+	}
+	if fake.requestCount() != 6 {
+		t.Errorf("Expected 6 requests, got %d", fake.requestCount())
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	limiter := requests.NewRateLimiter(0.1, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal("The first request has to be allowed right away!", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); errors.Is(err, context.DeadlineExceeded) == false {
+		t.Errorf("Expected the wait to be aborted by the context, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"gomensa/requests"
+	"io"
+	"strings"
+	"testing"
+)
+
+//upperRenderer is a text renderer writing everything in upper case, it checks that new formats can be registered
+type upperRenderer struct {
+	requests.Renderer
+}
+
+func (r upperRenderer) RenderCanteen(w io.Writer, canteen requests.Canteen) error {
+	var text bytes.Buffer
+	if err := r.Renderer.RenderCanteen(&text, canteen); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, strings.ToUpper(text.String()))
+	return err
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	//the registry is global, so the format is only registered once when the test is repeated
+	if _, err := requests.NewRenderer("upper", requests.RenderOptions{}); err != nil {
+		requests.RegisterRenderer("upper", func(options requests.RenderOptions) requests.Renderer {
+			text, _ := requests.NewRenderer("text", options)
+			return upperRenderer{text}
+		})
+	}
+
+	names := "," + strings.Join(requests.Renderers(), ",") + ","
+	for _, name := range []string{"text", "json", "jsonl", "csv", "upper"} {
+		if strings.Contains(names, ","+name+",") == false {
+			t.Errorf("Expected the format %s to be registered, got %s", name, names)
+		}
+	}
+
+	var output bytes.Buffer
+	if err := newRenderer(t, " UPPER ", requests.RenderOptions{}).RenderCanteen(&output, requests.Canteen{ID: 63, Name: "Mensa am Park"}); err != nil {
+		t.Fatal("Could not render the canteen!", err)
+	}
+	if strings.Contains(output.String(), "MENSA AM PARK") == false {
+		t.Errorf("Expected the output of the registered renderer, got %q", output.String())
+	}
+
+	if _, err := requests.NewRenderer("yaml", requests.RenderOptions{}); errors.Is(err, requests.ErrUnknownFormat) == false {
+		t.Errorf("Expected ErrUnknownFormat for yaml, got %v", err)
+	}
+}
+
+func TestRenderMealsText(t *testing.T) {
+	canteen := requests.Canteen{ID: 63, Name: "Mensa am Park"}
+	meals := []requests.CanteenMeal{{Name: "Nudeln", Category: "Pasta", Notes: []string{"vegetarisch"}}}
+	meals[0].Prices.Students = 2.5
+	meals[0].Prices.Employees = 4
+
+	var output bytes.Buffer
+	renderer := newRenderer(t, "text", requests.RenderOptions{ShowPrice: true, ShowCategory: true, PriceGroup: requests.PriceStudents})
+	if err := renderer.RenderMeals(&output, canteen, requests.NewMealsOfDay(requests.CanteenDate{Date: "2020-01-06"}, meals, nil)); err != nil {
+		t.Fatal("Could not render the meals!", err)
+	}
+	expected := "Mensa am Park meals for date: 2020-01-06:\n1 Meal: Nudeln\n\tCategorie:\n\t\t- Pasta\n\tPrice:\n\t\t- students: 2.50€\n\n"
+	if output.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, output.String())
+	}
+
+	output.Reset()
+	closed := requests.NewMealsOfDay(requests.CanteenDate{Date: "2020-01-08", Closed: true}, nil, &requests.ClosedError{Date: "2020-01-08", NextOpenDate: "2020-01-10"})
+	if err := renderer.RenderMeals(&output, canteen, closed); err != nil {
+		t.Fatal("Could not render the closed day!", err)
+	}
+	if output.String() != "The mensa is closed on Wednesday 2020-01-08, the next open day is Friday 2020-01-10.\n" {
+		t.Errorf("Expected the next open day, got %q", output.String())
+	}
+
+	output.Reset()
+	mealsErr := &requests.MealsError{Days: []requests.DayError{{Date: "2020-01-07", Err: requests.ErrServer}}}
+	if err := renderer.RenderWeek(&output, canteen, requests.NewMealsOfDays(outputDates, outputMeals, mealsErr)); err != nil {
+		t.Fatal("Could not render the week!", err)
+	}
+	for _, expected := range []string{"Mensa am Park meals for dates: 2020-01-06 - 2020-01-08\n", "-> 2020-01-07:\nCould not retrieve the meals of this day!\n", "-> 2020-01-08:\nclosed\n"} {
+		if strings.Contains(output.String(), expected) == false {
+			t.Errorf("Expected %q in the week, got %q", expected, output.String())
+		}
+	}
+}
@@ -1,13 +1,17 @@
 package tests
 
 import (
+	"errors"
 	"fmt"
 	"gomensa/requests"
 	"testing"
 )
 
 func TestRequestAllCanteens(t *testing.T) {
-	canteens := requests.RequestListOfAllCanteens()
+	canteens, err := requests.RequestListOfAllCanteens()
+	if err != nil {
+		t.Error("Could not request the list of all canteens!", err)
+	}
 	if len(canteens) == 0 {
 		t.Error("Did not retrieve any canteens when requesting a list of them!")
 	} else {
@@ -16,56 +20,56 @@ func TestRequestAllCanteens(t *testing.T) {
 }
 
 func TestRequestCanteenByID(t *testing.T) {
-	canteen := requests.RequestCanteenByID(1)
-	if canteen == nil {
-		t.Error("Could not retrieve single canteen by ID!")
+	canteen, err := requests.RequestCanteenByID(1)
+	if err != nil {
+		t.Error("Could not retrieve single canteen by ID!", err)
 	} else {
 		fmt.Println(*canteen)
 	}
 
 	id := 6666
-	canteen = requests.RequestCanteenByID(uint32(id))
-	if canteen != nil {
+	canteen, err = requests.RequestCanteenByID(uint32(id))
+	if errors.Is(err, requests.ErrCanteenNotFound) == false || canteen != nil {
 		t.Errorf("This canteen with ID %d should not exist!", id)
 	}
 }
 
 func TestVariousCanteenDates(t *testing.T) {
-	canteenWeek, _ := requests.RequestCanteenWeek(32)
-	if len(canteenWeek) == 0 {
-		t.Error("Something went wrong, we sould definetly retrieve a week of canteen dates here!")
+	canteenWeek, err := requests.RequestCanteenWeek(32)
+	if err != nil || len(canteenWeek) == 0 {
+		t.Error("Something went wrong, we sould definetly retrieve a week of canteen dates here!", err)
 	} else {
 		fmt.Println(canteenWeek)
 	}
 
-	canteenDay, _ := requests.RequestCanteenDateToday(32)
-	if len(canteenDay.Date) == 0 {
-		t.Error("Something went wrong, we sould definetly retrieve a canteenDate for today!")
+	canteenDay, err := requests.RequestCanteenDateToday(32)
+	if err != nil || len(canteenDay.Date) == 0 {
+		t.Error("Something went wrong, we sould definetly retrieve a canteenDate for today!", err)
 	} else {
 		fmt.Println(*canteenDay)
 	}
 
-	canteenDay, _ = requests.RequestCanteenDateTomorrow(32)
-	if len(canteenDay.Date) == 0 {
-		t.Error("Something went wrong, we sould definetly retrieve a canteenDate for tomorrow!")
+	canteenDay, err = requests.RequestCanteenDateTomorrow(32)
+	if err != nil || len(canteenDay.Date) == 0 {
+		t.Error("Something went wrong, we sould definetly retrieve a canteenDate for tomorrow!", err)
 	} else {
 		fmt.Println(*canteenDay)
 	}
 }
 
 func TestVariousCanteenMealDates(t *testing.T) {
-	_, canteenMeals := requests.RequestCanteenMealOfToday(32)
-	if len(canteenMeals) == 0 {
-		t.Error("Could not retrieve the meals for today!")
+	_, canteenMeals, err := requests.RequestCanteenMealOfToday(32)
+	if err != nil || len(canteenMeals) == 0 {
+		t.Error("Could not retrieve the meals for today!", err)
 	}
 
-	_, canteenMeals = requests.RequestCanteenMealOfTomorrow(32)
-	if len(canteenMeals) == 0 {
-		t.Error("Could not retrieve the meals for tomorrow!")
+	_, canteenMeals, err = requests.RequestCanteenMealOfTomorrow(32)
+	if err != nil || len(canteenMeals) == 0 {
+		t.Error("Could not retrieve the meals for tomorrow!", err)
 	}
 
-	_, canteenWeekMeals := requests.RequestCanteenMealsOfWeek(32)
-	if len(canteenWeekMeals) == 0 {
-		t.Error("Could not retrieve the meals for week!")
+	_, canteenWeekMeals, err := requests.RequestCanteenMealsOfWeek(32)
+	if err != nil || len(canteenWeekMeals) == 0 {
+		t.Error("Could not retrieve the meals for week!", err)
 	}
 }
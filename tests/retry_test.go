@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"errors"
+	"gomensa/requests"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//newFlakyServer returns a server which answers the first failures requests with the given status code and afterwards with a canteen
+func newFlakyServer(t *testing.T, failures int32, statusCode int, retryAfter string) (*httptest.Server, *int32) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(statusCode)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"name":"Mensa"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &attempts
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	server, attempts := newFlakyServer(t, 2, http.StatusServiceUnavailable, "")
+	client := requests.NewClient(server.URL)
+	client.Retry = requests.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	canteen, err := client.RequestCanteenByID(1)
+	if err != nil || canteen.ID != 1 {
+		t.Fatal("The request was not retried until it succeeded!", err)
+	}
+	if *attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", *attempts)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server, attempts := newFlakyServer(t, 10, http.StatusTooManyRequests, "1")
+	client := requests.NewClient(server.URL)
+	//the Retry-After of one second is limited by the MaxDelay
+	client.Retry = requests.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 20 * time.Millisecond}
+
+	start := time.Now()
+	_, err := client.RequestCanteenByID(1)
+	if errors.Is(err, requests.ErrRateLimited) == false {
+		t.Errorf("Expected ErrRateLimited after the last attempt, got %v", err)
+	}
+	if *attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", *attempts)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("The Retry-After header was not limited by the MaxDelay")
+	}
+
+	var apiErr *requests.APIError
+	if errors.As(err, &apiErr) == false || apiErr.RetryAfter != time.Second {
+		t.Errorf("Expected the APIError to carry the Retry-After of one second, got %v", err)
+	}
+}
+
+func TestRetryNotFound(t *testing.T) {
+	server, attempts := newFlakyServer(t, 10, http.StatusNotFound, "")
+	client := requests.NewClient(server.URL)
+	client.Retry = requests.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}
+
+	_, err := client.RequestCanteenByID(1)
+	if errors.Is(err, requests.ErrCanteenNotFound) == false {
+		t.Errorf("Expected ErrCanteenNotFound, got %v", err)
+	}
+	if *attempts != 1 {
+		t.Errorf("A 404 response must not be retried, got %d attempts", *attempts)
+	}
+}
+
+func TestRetryWithoutDelay(t *testing.T) {
+	server, attempts := newFlakyServer(t, 2, http.StatusServiceUnavailable, "")
+	client := requests.NewClient(server.URL)
+	//a BaseDelay of 0 retries immediately instead of waiting the MaxDelay
+	client.Retry = requests.RetryPolicy{MaxAttempts: 3, MaxDelay: 10 * time.Second}
+
+	start := time.Now()
+	if _, err := client.RequestCanteenByID(1); err != nil {
+		t.Fatal("Expected the third attempt to succeed!", err)
+	}
+	if *attempts != 3 || time.Since(start) > time.Second {
+		t.Errorf("Expected 3 attempts without waiting, got %d attempts in %s", *attempts, time.Since(start))
+	}
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"gomensa/requests"
+	"testing"
+)
+
+var searchCanteens = []requests.Canteen{
+	{ID: 63, Name: "Mensa am Park", City: "Leipzig", Address: "Universitätsstraße 5, 04109 Leipzig"},
+	{ID: 64, Name: "Mensa Academica", City: "Leipzig", Address: "Philipp-Rosenthal-Straße 31"},
+	{ID: 31, Name: "Mensa Süd", City: "München", Address: "Leopoldstraße 13a"},
+	{ID: 7, Name: "Cafeteria am Schloss", City: "Halle", Address: "Parkweg 2"},
+}
+
+func TestSearchCanteens(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []int
+	}{
+		{"leipzig park", []int{63}},
+		{"LEIPZIG", []int{63, 64}},
+		{"muenchen", []int{31}},
+		{"münchen", []int{31}},
+		{"sud", []int{31}},
+		{"universitaetsstrasse", []int{63}},
+		{"akademica", []int{64}},
+		//a match in the name is better than one in the address
+		{"park", []int{63, 7}},
+		{"berlin", []int{}},
+		{"", []int{}},
+	}
+
+	for _, test := range tests {
+		matches := requests.SearchCanteens(searchCanteens, test.query)
+		if len(matches) != len(test.expected) {
+			t.Errorf("Expected %d matches for %q, got %d", len(test.expected), test.query, len(matches))
+			continue
+		}
+		for i, match := range matches {
+			if match.Canteen.ID != test.expected[i] {
+				t.Errorf("Expected mensa %d at position %d for %q, got %d", test.expected[i], i, test.query, match.Canteen.ID)
+			}
+		}
+	}
+}
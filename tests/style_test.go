@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"gomensa/requests"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRenderMealsColored(t *testing.T) {
+	canteen := requests.Canteen{ID: 63, Name: "Mensa am Park"}
+	meals := []requests.CanteenMeal{{Name: "Linseneintopf", Category: "Suppe", Notes: []string{"vegan", "Sellerie"}}}
+	meals[0].Prices.Students = 1.5
+	days := requests.NewMealsOfDays([]requests.CanteenDate{{Date: "2020-01-06"}, {Date: "2020-01-07", Closed: true}}, [][]requests.CanteenMeal{meals, nil}, nil)
+
+	options := requests.RenderOptions{ShowPrice: true, ShowNotes: true, ShowCategory: true, PriceGroup: requests.PriceStudents}
+
+	var plain bytes.Buffer
+	if err := newRenderer(t, "text", options).RenderWeek(&plain, canteen, days); err != nil {
+		t.Fatal("Could not render the week!", err)
+	}
+	if strings.Contains(plain.String(), "\033[") {
+		t.Errorf("Expected no colors without the Color option, got %q", plain.String())
+	}
+
+	options.Color = true
+	var colored bytes.Buffer
+	if err := newRenderer(t, "text", options).RenderWeek(&colored, canteen, days); err != nil {
+		t.Fatal("Could not render the colored week!", err)
+	}
+	for _, expected := range []string{
+		"\t\033[36mCategorie:\033[0m\n\t\t\033[36m- Suppe\033[0m\n",
+		"\t\t\033[32m- vegan\033[0m\n\t\t- Sellerie\n",
+		"- students: \033[33m1.50€\033[0m",
+		"\033[31mclosed\033[0m",
+	} {
+		if strings.Contains(colored.String(), expected) == false {
+			t.Errorf("Expected %q in the colored week, got %q", expected, colored.String())
+		}
+	}
+
+	//without the escape sequences the colored output is the plain output
+	withoutColors := strings.NewReplacer("\033[0m", "", "\033[1m", "", "\033[31m", "", "\033[32m", "", "\033[33m", "", "\033[36m", "").Replace(colored.String())
+	if withoutColors != plain.String() {
+		t.Errorf("Expected the colors to only be added to the text output, got %q", withoutColors)
+	}
+}
+
+func TestRenderColoredMarkersInNames(t *testing.T) {
+	//names and notes which look like the headings of the text output keep their own style
+	canteen := requests.Canteen{ID: 63, Name: "closed", City: "Leipzig -> open"}
+	meals := []requests.CanteenMeal{{Name: "closed", Category: "Prices:", Notes: []string{"Notes:", "2.10€"}}}
+	day := requests.NewMealsOfDay(requests.CanteenDate{Date: "2020-01-06"}, meals, nil)
+	renderer := newRenderer(t, "text", requests.RenderOptions{ShowNotes: true, ShowCategory: true, Color: true})
+
+	var output bytes.Buffer
+	if err := renderer.RenderMeals(&output, canteen, day); err != nil {
+		t.Fatal("Could not render the meals!", err)
+	}
+	expected := "\033[1mclosed meals for date: 2020-01-06:\033[0m\n" +
+		"1 Meal: \033[1mclosed\033[0m\n\t\033[36mCategorie:\033[0m\n\t\t\033[36m- Prices:\033[0m\n" +
+		"\t\033[36mNotes:\033[0m\n\t\t- Notes:\n\t\t- 2.10€\n\n\n"
+	if output.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, output.String())
+	}
+
+	output.Reset()
+	if err := renderer.RenderCanteens(&output, []requests.Canteen{canteen}); err != nil {
+		t.Fatal("Could not render the canteens!", err)
+	}
+	if strings.Contains(output.String(), "\033[") {
+		t.Errorf("Expected no colors in the list of canteens, got %q", output.String())
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "output")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if requests.UseColor(requests.ColorAlways, file) == false || requests.UseColor(requests.ColorNever, file) {
+		t.Error("Expected always and never to ignore the output")
+	}
+	if requests.UseColor(requests.ColorAuto, file) {
+		t.Error("Expected no colors for a file in the auto mode")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if requests.UseColor(requests.ColorAuto, os.Stdout) {
+		t.Error("Expected no colors when NO_COLOR is set")
+	}
+
+	if mode, err := requests.ParseColorMode(""); err != nil || mode != requests.ColorAuto {
+		t.Errorf("Expected auto for an empty color mode, got %q: %v", mode, err)
+	}
+	if _, err := requests.ParseColorMode("blue"); errors.Is(err, requests.ErrUnknownColorMode) == false {
+		t.Errorf("Expected ErrUnknownColorMode for blue, got %v", err)
+	}
+}
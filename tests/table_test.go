@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"bytes"
+	"gomensa/requests"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRenderMealsTable(t *testing.T) {
+	canteen := requests.Canteen{ID: 63, Name: "Mensa am Park"}
+	meals := []requests.CanteenMeal{
+		{Name: "Vegetarische Gemüsepfanne mit Reis und einer sehr langen Beschreibung der Beilagen", Category: "Vegetarisch"},
+		{Name: "Grüße aus Köln", Category: "Süßspeise"},
+	}
+	meals[0].Prices.Students = 2.1
+	meals[1].Prices.Students = 1.5
+	meals[1].Prices.Others = 3
+
+	var output bytes.Buffer
+	renderer := newRenderer(t, "table", requests.RenderOptions{Width: 70})
+	if err := renderer.RenderMeals(&output, canteen, requests.NewMealsOfDay(requests.CanteenDate{Date: "2020-01-06"}, meals, nil)); err != nil {
+		t.Fatal("Could not render the table!", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if lines[0] != "Mensa am Park - Monday 2020-01-06" {
+		t.Errorf("Expected the canteen and the day as title, got %q", lines[0])
+	}
+
+	//the table starts after the title and an empty line, every column separator has the same position in all lines
+	table := lines[2:]
+	separators := separatorColumns(table[1], '+')
+	for _, line := range table {
+		if width := utf8.RuneCountInString(line); width > 70 {
+			t.Errorf("Expected lines of at most 70 columns, got %d: %q", width, line)
+		}
+		if line == table[1] {
+			continue
+		}
+		//continuation lines of wrapped cells leave out the empty cells at their end
+		if columns := separatorColumns(line, '|'); columns[0] != separators[0] || columns[1] != separators[1] {
+			t.Errorf("Expected aligned columns %v, got %v in %q", separators, columns, line)
+		}
+	}
+
+	if len(table) < 5 || strings.HasPrefix(table[3], "  |             | ") == false {
+		t.Errorf("Expected the long meal name to be wrapped, got %q", output.String())
+	}
+	if strings.Contains(output.String(), "|    1.50€ |         - |  3.00€\n") == false {
+		t.Errorf("Expected the prices right aligned and - for unknown prices, got %q", output.String())
+	}
+}
+
+func TestRenderWeekTable(t *testing.T) {
+	canteen := requests.Canteen{ID: 63, Name: "Mensa am Park"}
+	mealsErr := &requests.MealsError{Days: []requests.DayError{{Date: "2020-01-07", Err: requests.ErrServer}}}
+
+	var output bytes.Buffer
+	renderer := newRenderer(t, "table", requests.RenderOptions{ShowNotes: true, PriceGroup: requests.PriceStudents})
+	if err := renderer.RenderWeek(&output, canteen, requests.NewMealsOfDays(outputDates, outputMeals, mealsErr)); err != nil {
+		t.Fatal("Could not render the week!", err)
+	}
+
+	for _, expected := range []string{
+		"== Monday 2020-01-06 ==\n# | Category | Meal               | Students | Notes\n",
+		"== Tuesday 2020-01-07 ==\nCould not retrieve the meals of this day!\n",
+		"== Wednesday 2020-01-08 ==\nclosed\n",
+	} {
+		if strings.Contains(output.String(), expected) == false {
+			t.Errorf("Expected %q in the week, got %q", expected, output.String())
+		}
+	}
+}
+
+func TestRenderCanteensTableWideCharacters(t *testing.T) {
+	canteens := []requests.Canteen{{ID: 1, Name: "学生食堂", City: "東京"}, {ID: 2, Name: "Mensa", City: "Köln"}}
+
+	var output bytes.Buffer
+	if err := newRenderer(t, "table", requests.RenderOptions{}).RenderCanteens(&output, canteens); err != nil {
+		t.Fatal("Could not render the canteens!", err)
+	}
+
+	//the chinese characters need two columns each, so the name column is 8 columns wide
+	lines := strings.Split(output.String(), "\n")
+	if lines[2] != " 1 | 学生食堂 | 東京" {
+		t.Errorf("Unexpected row with wide characters %q", lines[2])
+	}
+	if lines[3] != " 2 | Mensa    | Köln" {
+		t.Errorf("Expected the name padded to the width of the wide characters, got %q", lines[3])
+	}
+}
+
+//separatorColumns returns the rune positions of the separator in line
+func separatorColumns(line string, separator rune) []int {
+	columns := []int{}
+	for i, r := range []rune(line) {
+		if r == separator {
+			columns = append(columns, i)
+		}
+	}
+	return columns
+}
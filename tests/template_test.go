@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"bytes"
+	"gomensa/requests"
+	"strings"
+	"testing"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	canteen := requests.Canteen{ID: 63, Name: "Mensa am Park"}
+	meals := []requests.CanteenMeal{
+		{Name: "Vegetarische Gemüsepfanne mit Reis", Notes: []string{"vegetarisch", "Sellerie"}},
+		{Name: "Currywurst", Notes: []string{"Schwein"}},
+	}
+	meals[0].Prices.Students = 2.1
+	day := requests.NewMealsOfDay(requests.CanteenDate{Date: "2020-01-31"}, meals, nil)
+
+	tests := map[string]string{
+		`{{.Canteen.Name}} {{date "Mon 02.01." .Day.Date}} {{weekday .Day.Date}}`:                                   "Mensa am Park Fri 31.01. Friday",
+		`{{range $i, $meal := .Day.Meals}}{{add $i 1}}. {{truncate 12 .Name}} {{price .Prices "Students"}};{{end}}`: "1. Vegetarisch… 2.10€;2. Currywurst -;",
+		`{{range .Days}}{{range .Meals}}{{join (filterNotes .Notes "VEG" "schwein") ","}}|{{end}}{{end}}`:           "vegetarisch|Schwein|",
+		`{{range .Day.Meals}}{{join (withoutNotes .Notes "veg") ","}}{{if hasNote .Notes "sell"}}!{{end}}|{{end}}`:  "Sellerie!|Schwein|",
+		`{{range .Day.Meals}}{{if veggie .Notes}}{{.Name}}{{end}}{{end}}`:                                           "Vegetarische Gemüsepfanne mit Reis",
+	}
+	for text, expected := range tests {
+		renderer, err := requests.NewTemplateRenderer(text, requests.RenderOptions{})
+		if err != nil {
+			t.Errorf("Could not parse the template %q: %v", text, err)
+			continue
+		}
+		var output bytes.Buffer
+		if err := renderer.RenderMeals(&output, canteen, day); err != nil {
+			t.Errorf("Could not execute the template %q: %v", text, err)
+			continue
+		}
+		if output.String() != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, text, output.String())
+		}
+	}
+
+	if _, err := requests.NewTemplateRenderer("{{.Day", requests.RenderOptions{}); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	canteen := requests.Canteen{ID: 63, Name: "Mensa am Park", City: "Leipzig"}
+	days := requests.NewMealsOfDays(outputDates, outputMeals, nil)
+
+	for _, name := range requests.BuiltinTemplates() {
+		renderer, err := requests.NewTemplateRenderer(name, requests.RenderOptions{})
+		if err != nil {
+			t.Fatalf("Could not parse the built-in template %s: %v", name, err)
+		}
+		//every built-in template has to work for all commands
+		var output bytes.Buffer
+		if err := renderer.RenderWeek(&output, canteen, days); err != nil {
+			t.Errorf("Could not render the week with %s: %v", name, err)
+		}
+		if err := renderer.RenderMeals(&output, canteen, days[0]); err != nil {
+			t.Errorf("Could not render a day with %s: %v", name, err)
+		}
+		if err := renderer.RenderCanteens(&output, []requests.Canteen{canteen}); err != nil {
+			t.Errorf("Could not render the canteens with %s: %v", name, err)
+		}
+		if err := renderer.RenderDates(&output, canteen, outputDates); err != nil {
+			t.Errorf("Could not render the dates with %s: %v", name, err)
+		}
+	}
+
+	renderer, err := requests.NewTemplateRenderer("oneline", requests.RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var output bytes.Buffer
+	if err := renderer.RenderWeek(&output, canteen, days); err != nil {
+		t.Fatal(err)
+	}
+	if output.String() != "Mon 06.01.: Nudeln, mit \"Soße\"\nTue 07.01.: \nWed 08.01.: closed\n" {
+		t.Errorf("Unexpected output of the oneline template %q", output.String())
+	}
+
+	renderer, err = requests.NewTemplateRenderer("tmux", requests.RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output.Reset()
+	if err := renderer.RenderMeals(&output, canteen, days[0]); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(output.String(), "\n") != 1 || strings.HasPrefix(output.String(), "Nudeln") == false {
+		t.Errorf("Expected the meals on a single line, got %q", output.String())
+	}
+}